@@ -25,3 +25,16 @@ type Sections struct {
 	Pubsub   bool
 	Profiler bool
 }
+
+// disabled returns the config keys of the built-in sections that are not
+// enabled, so their struct fields are not validated.
+func (s Sections) disabled() map[string]bool {
+	return map[string]bool{
+		"log":      !s.Log,
+		"postgres": !s.Postgres,
+		"redis":    !s.Redis,
+		"consul":   !s.Consul,
+		"pubsub":   !s.Pubsub,
+		"profiler": !s.Profiler,
+	}
+}
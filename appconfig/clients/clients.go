@@ -0,0 +1,15 @@
+// Package clients builds ready-to-use clients from the canonical appconfig
+// sections, so services do not repeat the glue between config and client:
+//
+//	logger, closer, err := clients.NewLogger(cfg.Log, nil)
+//
+// It depends on the standard library only. The Redis, pgw, RabbitMQ and
+// Consul clients are built by the redisclient, pgwclient, rabbitmqclient and
+// consulclient modules below it, so each client library is pulled in only
+// where it is used.
+//
+// Setting up OpenTelemetry logging (LoggerProvider, exporter, otelslog
+// bridge) is out of scope: it would tie every user to the OTel SDK, and
+// services already configure it alongside their tracer. NewLogger takes the
+// resulting slog.Handler instead.
+package clients
@@ -0,0 +1,39 @@
+package clients_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/webitel/webitel-go-kit/appconfig"
+	"github.com/webitel/webitel-go-kit/appconfig/clients"
+)
+
+func TestNewLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "svc.log")
+	logger, closer, err := clients.NewLogger(appconfig.Log{Level: "warn", JSON: true, File: path}, nil)
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	logger.Info("dropped")
+	logger.Warn("kept", "k", "v")
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "dropped") {
+		t.Errorf("info record should be filtered at warn level:\n%s", out)
+	}
+	if !strings.Contains(string(out), `"msg":"kept","k":"v"`) {
+		t.Errorf("want a JSON warn record:\n%s", out)
+	}
+
+	if _, _, err := clients.NewLogger(appconfig.Log{Level: "verbose"}, nil); err == nil {
+		t.Error("want error for an unknown level")
+	}
+}
@@ -0,0 +1,43 @@
+// Package consulclient builds a Consul discovery provider from the canonical
+// appconfig Consul section:
+//
+//	provider, err := consulclient.NewConsul(cfg.Consul, logger)
+//
+// It is a module of its own, so that only services that use service
+// discovery pull it in.
+package consulclient
+
+import (
+	"github.com/hashicorp/consul/api"
+	"github.com/webitel/webitel-go-kit/appconfig"
+	"github.com/webitel/webitel-go-kit/infra/discovery"
+	"github.com/webitel/webitel-go-kit/infra/discovery/consul"
+)
+
+// NewConsul connects to the Consul agent at cfg.Addr with the ACL token
+// cfg.Token, and runs the catalog and KV calls in datacenter
+// cfg.Datacenter. Empty fields fall back to the CONSUL_HTTP_* env vars and
+// the agent defaults. opts are applied to the provider in order.
+func NewConsul(
+	cfg appconfig.Consul,
+	logger discovery.Logger,
+	opts ...discovery.Option[discovery.DiscoveryProvider],
+) (discovery.DiscoveryProvider, error) {
+	apiCfg := api.DefaultConfig()
+	if cfg.Addr != "" {
+		apiCfg.Address = cfg.Addr
+	}
+	if cfg.Token != "" {
+		apiCfg.Token = cfg.Token
+	}
+	apiCfg.Datacenter = cfg.Datacenter
+
+	r, err := consul.NewConsulRegistryWithConfig(apiCfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
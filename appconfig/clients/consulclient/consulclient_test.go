@@ -0,0 +1,61 @@
+package consulclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/webitel/webitel-go-kit/appconfig"
+	"github.com/webitel/webitel-go-kit/appconfig/clients/consulclient"
+)
+
+func TestNewConsul(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen = map[string]*http.Request{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path] = r
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/agent/self":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Config": map[string]any{"NodeName": "node1"}})
+		case "/v1/kv/config/svc":
+			_ = json.NewEncoder(w).Encode(api.KVPairs{{Key: "config/svc", Value: []byte("log: {}")}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := consulclient.NewConsul(appconfig.Consul{
+		Addr:       strings.TrimPrefix(server.URL, "http://"),
+		Token:      "acl-token",
+		Datacenter: "dc2",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewConsul: %v", err)
+	}
+	if _, err := provider.KV().GetFromKV(context.Background(), "config/svc"); err != nil {
+		t.Fatalf("GetFromKV: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	agent, kv := seen["/v1/agent/self"], seen["/v1/kv/config/svc"]
+	if agent == nil || kv == nil {
+		t.Fatalf("want agent and KV requests, got %v", seen)
+	}
+	if got := agent.Header.Get("X-Consul-Token"); got != "acl-token" {
+		t.Errorf("token: want acl-token, got %q", got)
+	}
+	if got := kv.URL.Query().Get("dc"); got != "dc2" {
+		t.Errorf("datacenter: want dc2, got %q", got)
+	}
+}
@@ -0,0 +1,107 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/webitel/webitel-go-kit/appconfig"
+)
+
+// NewLogger builds a *slog.Logger from cfg, writing to stdout (Console)
+// and/or a file (File), as JSON or text, at cfg.Level.
+//
+// When cfg.Otel is set, records are also passed to otel, typically
+// otelslog.NewHandler(name) over the LoggerProvider the service set up; a
+// nil otel disables the bridge. NewLogger does not build that handler. Close the returned io.Closer on shutdown to close
+// the log file.
+func NewLogger(cfg appconfig.Log, otel slog.Handler) (*slog.Logger, io.Closer, error) {
+	var level slog.Level
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, nil, fmt.Errorf("clients: log level: %w", err)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	newHandler := func(w io.Writer) slog.Handler {
+		if cfg.JSON {
+			return slog.NewJSONHandler(w, opts)
+		}
+		return slog.NewTextHandler(w, opts)
+	}
+
+	var (
+		handlers []slog.Handler
+		closer   io.Closer = nopCloser{}
+	)
+	if cfg.Console {
+		handlers = append(handlers, newHandler(os.Stdout))
+	}
+	if cfg.File != "" {
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("clients: open log file: %w", err)
+		}
+		handlers = append(handlers, newHandler(f))
+		closer = f
+	}
+	if cfg.Otel && otel != nil {
+		handlers = append(handlers, otel)
+	}
+
+	var h slog.Handler
+	switch len(handlers) {
+	case 0:
+		h = slog.NewTextHandler(io.Discard, opts)
+	case 1:
+		h = handlers[0]
+	default:
+		h = fanout(handlers)
+	}
+	return slog.New(h), closer, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// fanout passes every record to all handlers that are enabled for it.
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
@@ -0,0 +1,37 @@
+// Package pgwclient builds a pgw.PoolManager from the canonical appconfig
+// Postgres section:
+//
+//	manager, err := pgwclient.NewPoolManager(ctx, cfg.Postgres)
+//
+// It is a module of its own, so that only services that use pgw pull it in.
+package pgwclient
+
+import (
+	"context"
+	"slices"
+
+	"github.com/webitel/webitel-go-kit/appconfig"
+	"github.com/webitel/webitel-go-kit/infra/pgw"
+)
+
+// NewPoolManager connects a pgw.PoolManager to the primary at cfg.DSN and the
+// standbys at cfg.StandbyDSNs, see WithPostgres. opts configure the rest,
+// e.g. health checks or the tracer.
+func NewPoolManager(ctx context.Context, cfg appconfig.Postgres, opts ...pgw.ConfigOption) (*pgw.PoolManager, error) {
+	return pgw.NewPoolManager(ctx, append(slices.Clip(opts), WithPostgres(cfg))...)
+}
+
+// WithPostgres returns a pgw.ConfigOption that sets the primary DSN, the
+// standby DSNs and, when cfg.MaxOpenConns is set, the MaxConns of both
+// pools. It only sets those fields, so apply it after WithPrimaryConfig and
+// WithStandbyConfig, which replace their whole section.
+func WithPostgres(cfg appconfig.Postgres) pgw.ConfigOption {
+	return func(c *pgw.Config) {
+		c.PrimaryConfig.DSN = cfg.DSN
+		c.StandbyConfig.DSN = cfg.StandbyDSNs
+		if cfg.MaxOpenConns > 0 {
+			c.PrimaryConfig.MaxConns = cfg.MaxOpenConns
+			c.StandbyConfig.MaxConns = cfg.MaxOpenConns
+		}
+	}
+}
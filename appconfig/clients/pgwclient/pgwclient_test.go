@@ -0,0 +1,36 @@
+package pgwclient_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/webitel/webitel-go-kit/appconfig"
+	"github.com/webitel/webitel-go-kit/appconfig/clients/pgwclient"
+	"github.com/webitel/webitel-go-kit/infra/pgw"
+)
+
+func TestWithPostgres(t *testing.T) {
+	var cfg pgw.Config
+	pgw.WithPrimaryConfig(pgw.PrimaryConfig{DSN: "postgres://old/db", MaxConns: 4, HealthCheckInterval: 3})(&cfg)
+	pgwclient.WithPostgres(appconfig.Postgres{
+		DSN:          "postgres://pg1:5432,pg2:5432/app",
+		StandbyDSNs:  []string{"postgres://replica1/app", "postgres://replica2/app"},
+		MaxOpenConns: 20,
+	})(&cfg)
+
+	if cfg.PrimaryConfig.DSN != "postgres://pg1:5432,pg2:5432/app" || cfg.PrimaryConfig.MaxConns != 20 {
+		t.Errorf("primary: got dsn=%q max_conns=%d", cfg.PrimaryConfig.DSN, cfg.PrimaryConfig.MaxConns)
+	}
+	if cfg.PrimaryConfig.HealthCheckInterval != 3 {
+		t.Errorf("primary: other settings should be kept, got health_check_interval=%v", cfg.PrimaryConfig.HealthCheckInterval)
+	}
+	if !slices.Equal(cfg.StandbyConfig.DSN, []string{"postgres://replica1/app", "postgres://replica2/app"}) || cfg.StandbyConfig.MaxConns != 20 {
+		t.Errorf("standby: got dsn=%v max_conns=%d", cfg.StandbyConfig.DSN, cfg.StandbyConfig.MaxConns)
+	}
+
+	cfg = pgw.Config{PrimaryConfig: pgw.PrimaryConfig{MaxConns: 4}}
+	pgwclient.WithPostgres(appconfig.Postgres{DSN: "postgres://db/app"})(&cfg)
+	if cfg.PrimaryConfig.MaxConns != 4 {
+		t.Errorf("unset max_open_conns should keep MaxConns, got %d", cfg.PrimaryConfig.MaxConns)
+	}
+}
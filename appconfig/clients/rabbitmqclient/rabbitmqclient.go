@@ -0,0 +1,38 @@
+// Package rabbitmqclient builds a rabbitmq.Connection from the canonical
+// appconfig Pubsub section:
+//
+//	conn, err := rabbitmqclient.NewRabbitMQ(cfg.Pubsub, logger)
+//
+// It is a module of its own, so that only services that use RabbitMQ pull
+// it in.
+package rabbitmqclient
+
+import (
+	"fmt"
+
+	"github.com/webitel/webitel-go-kit/appconfig"
+	"github.com/webitel/webitel-go-kit/infra/pubsub/rabbitmq"
+)
+
+// NewRabbitMQ connects to the broker at cfg.URL, see NewConfig.
+func NewRabbitMQ(cfg appconfig.Pubsub, logger rabbitmq.Logger, opts ...rabbitmq.ConfigOption) (*rabbitmq.Connection, error) {
+	rmqCfg, err := NewConfig(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return rabbitmq.NewConnection(rmqCfg, logger)
+}
+
+// NewConfig returns the rabbitmq.Config of cfg: its URL and, when cfg.TLS is
+// enabled, the TLS configuration of amqps:// URLs. opts are applied after.
+// Returns an error when cfg.Driver names another broker.
+func NewConfig(cfg appconfig.Pubsub, opts ...rabbitmq.ConfigOption) (*rabbitmq.Config, error) {
+	if cfg.Driver != "" && cfg.Driver != "rabbitmq" {
+		return nil, fmt.Errorf("rabbitmqclient: pubsub driver %q is not rabbitmq", cfg.Driver)
+	}
+	tlsCfg, err := cfg.TLS.Config()
+	if err != nil {
+		return nil, err
+	}
+	return rabbitmq.NewConfig(cfg.URL, append([]rabbitmq.ConfigOption{rabbitmq.WithTLSConfig(tlsCfg)}, opts...)...)
+}
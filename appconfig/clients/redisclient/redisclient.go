@@ -0,0 +1,36 @@
+// Package redisclient builds a go-redis client from the canonical appconfig
+// Redis section:
+//
+//	rdb, err := redisclient.NewRedis(cfg.Redis)
+//
+// It is a module of its own, so that only services that use Redis pull it in.
+package redisclient
+
+import (
+	"github.com/redis/go-redis/v9"
+	"github.com/webitel/webitel-go-kit/appconfig"
+)
+
+// NewRedis builds a go-redis client from cfg: a Sentinel-backed failover
+// client when cfg.Sentinel.Master is set, a single-node client otherwise.
+// The client connects lazily; call Ping to check reachability.
+func NewRedis(cfg appconfig.Redis) (redis.UniversalClient, error) {
+	tlsCfg, err := cfg.TLS.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:     []string{cfg.Addr},
+		Username:  cfg.Username,
+		Password:  cfg.Password,
+		DB:        cfg.DB,
+		TLSConfig: tlsCfg,
+	}
+	if cfg.Sentinel.Master != "" {
+		opts.MasterName = cfg.Sentinel.Master
+		opts.Addrs = cfg.Sentinel.Addrs
+		opts.SentinelPassword = cfg.Sentinel.Password
+	}
+	return redis.NewUniversalClient(opts), nil
+}
@@ -0,0 +1,38 @@
+package redisclient_test
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/webitel/webitel-go-kit/appconfig"
+	"github.com/webitel/webitel-go-kit/appconfig/clients/redisclient"
+)
+
+func TestNewRedis(t *testing.T) {
+	rdb, err := redisclient.NewRedis(appconfig.Redis{Addr: "redis:6379", Username: "svc", DB: 2})
+	if err != nil {
+		t.Fatalf("NewRedis: %v", err)
+	}
+	defer rdb.Close()
+	opts := rdb.(*redis.Client).Options()
+	if opts.Addr != "redis:6379" || opts.Username != "svc" || opts.DB != 2 || opts.TLSConfig != nil {
+		t.Errorf("single node: got addr=%q user=%q db=%d tls=%v", opts.Addr, opts.Username, opts.DB, opts.TLSConfig)
+	}
+
+	rdb, err = redisclient.NewRedis(appconfig.Redis{
+		Addr:     "ignored:6379",
+		Sentinel: appconfig.RedisSentinel{Master: "mymaster", Addrs: []string{"s1:26379", "s2:26379"}},
+		TLS:      appconfig.ClientTLS{Enabled: true, ServerName: "redis.internal"},
+	})
+	if err != nil {
+		t.Fatalf("NewRedis sentinel: %v", err)
+	}
+	defer rdb.Close()
+	opts = rdb.(*redis.Client).Options()
+	if opts.Addr != "FailoverClient" {
+		t.Errorf("sentinel: want a failover client, got addr %q", opts.Addr)
+	}
+	if opts.TLSConfig == nil || opts.TLSConfig.ServerName != "redis.internal" {
+		t.Errorf("sentinel: TLS config not applied: %+v", opts.TLSConfig)
+	}
+}
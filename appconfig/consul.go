@@ -1,7 +1,12 @@
 package appconfig
 
 // Consul holds Consul service-discovery configuration.
-// Env var: CONSUL_ADDR.
+// Env vars: CONSUL_ADDR, CONSUL_TOKEN, CONSUL_DATACENTER.
+//
+// Token is the ACL token; empty uses the agent's default token. Datacenter
+// empty uses the agent's datacenter.
 type Consul struct {
-	Addr string `mapstructure:"addr"`
+	Addr       string `mapstructure:"addr"`
+	Token      string `mapstructure:"token"`
+	Datacenter string `mapstructure:"datacenter"`
 }
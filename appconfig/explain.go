@@ -0,0 +1,239 @@
+package appconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ErrPrintConfig is returned by Load after it printed the effective config
+// because --print-config was set. Like pflag.ErrHelp, the caller should exit
+// successfully:
+//
+//	if errors.Is(err, appconfig.ErrPrintConfig) { os.Exit(0) }
+var ErrPrintConfig = errors.New("appconfig: config printed")
+
+// Source is where the effective value of a key came from.
+type Source string
+
+const (
+	SourceFlag    Source = "flag"
+	SourceEnv     Source = "env"
+	SourceRemote  Source = "remote"
+	SourceFile    Source = "file"
+	SourceDefault Source = "default"
+)
+
+// redacted replaces secret values in Explain and PrintConfig output.
+const redacted = "[REDACTED]"
+
+// Setting is one key of the effective config, see Explain.
+type Setting struct {
+	Key    string // e.g. "postgres.dsn"
+	Value  any    // secrets are redacted
+	Source Source
+}
+
+// Explain returns every key of the effective config, sorted, with its value
+// and the source it came from, following the precedence
+// flag > env > remote > file > default.
+//
+// Values are redacted when they came from a secret reference (see IsSecret),
+// when the key names a password, secret, token or TLS key, and, for URLs and
+// DSNs, the password is masked.
+func (l *Loader) Explain() []Setting {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	keys := l.v.AllKeys()
+	sort.Strings(keys)
+	settings := make([]Setting, 0, len(keys))
+	for _, key := range keys {
+		if key == printConfigFlag {
+			continue
+		}
+		settings = append(settings, Setting{
+			Key:    key,
+			Value:  redact(key, l.v.Get(key), l.secrets[key]),
+			Source: l.source(key),
+		})
+	}
+	return settings
+}
+
+// PrintConfig writes the output of Explain to w as "yaml" (nested, with the
+// source as a line comment) or "json" (keyed by the full key, with value
+// and source).
+func (l *Loader) PrintConfig(w io.Writer, format string) error {
+	settings := l.Explain()
+	switch format {
+	case "json":
+		out := make(map[string]any, len(settings))
+		for _, s := range settings {
+			out[s.Key] = map[string]any{"value": s.Value, "source": s.Source}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	case "yaml", "yml", "":
+		root, err := settingsNode(settings)
+		if err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(root); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("appconfig: unsupported print format %q, want yaml or json", format)
+	}
+}
+
+// printConfig handles --print-config: it prints the effective config to
+// stdout and returns ErrPrintConfig, joined with the unmarshal error if any.
+func (l *Loader) printConfig(format string, target any) error {
+	// Unmarshal first to learn which keys hold secrets; print even an invalid
+	// config, which is when it is most useful.
+	uerr := l.Unmarshal(target)
+	if err := l.PrintConfig(os.Stdout, format); err != nil {
+		return err
+	}
+	return errors.Join(ErrPrintConfig, uerr)
+}
+
+func (l *Loader) source(key string) Source {
+	if l.fs != nil {
+		if f := l.fs.Lookup(key); f != nil && f.Changed {
+			return SourceFlag
+		}
+	}
+	env := envName(key)
+	if key == profileFlag {
+		env = profileEnv
+	}
+	if os.Getenv(env) != "" || os.Getenv(env+"_FILE") != "" {
+		return SourceEnv
+	}
+	if lookupKey(l.remoteSettings, key) {
+		return SourceRemote
+	}
+	if l.v.InConfig(key) {
+		return SourceFile
+	}
+	return SourceDefault
+}
+
+// lookupKey reports whether the dotted key exists in the nested map m.
+func lookupKey(m map[string]any, key string) bool {
+	head, rest, nested := strings.Cut(key, ".")
+	v, ok := m[head]
+	if !ok || !nested {
+		return ok
+	}
+	sub, ok := v.(map[string]any)
+	return ok && lookupKey(sub, rest)
+}
+
+// sensitiveNames are key names (the last key segment, or its suffix after
+// "_") whose values are always redacted. "key" covers TLS key paths.
+var sensitiveNames = map[string]bool{
+	"password": true,
+	"secret":   true,
+	"token":    true,
+	"key":      true,
+}
+
+// dsnPassword matches the password of a key=value Postgres DSN.
+var dsnPassword = regexp.MustCompile(`(?i)(\bpassword\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+func redact(key string, v any, secret bool) any {
+	if secret {
+		return redacted
+	}
+	name := key[strings.LastIndexByte(key, '.')+1:]
+	if i := strings.LastIndexByte(name, '_'); i >= 0 && sensitiveNames[name[i+1:]] {
+		name = name[i+1:]
+	}
+	if sensitiveNames[name] {
+		if v == nil || v == "" {
+			return v
+		}
+		return redacted
+	}
+
+	switch v := v.(type) {
+	case string:
+		return redactDSN(v)
+	case []string:
+		out := make([]string, len(v))
+		for i, s := range v {
+			out[i] = redactDSN(s)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			if s, ok := e.(string); ok {
+				e = redactDSN(s)
+			}
+			out[i] = e
+		}
+		return out
+	}
+	return v
+}
+
+// redactDSN masks the password of a URL or key=value DSN.
+func redactDSN(s string) string {
+	if strings.Contains(s, "://") {
+		if u, err := url.Parse(s); err == nil {
+			if _, ok := u.User.Password(); ok {
+				return u.Redacted()
+			}
+		}
+		return s
+	}
+	return dsnPassword.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// settingsNode builds a nested YAML mapping from settings, with the source of
+// each value as its line comment.
+func settingsNode(settings []Setting) (*yaml.Node, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, s := range settings {
+		m := root
+		parts := strings.Split(s.Key, ".")
+		for _, p := range parts[:len(parts)-1] {
+			m = childMapping(m, p)
+		}
+		var val yaml.Node
+		if err := val.Encode(s.Value); err != nil {
+			return nil, fmt.Errorf("appconfig: encode %s: %w", s.Key, err)
+		}
+		val.LineComment = string(s.Source)
+		m.Content = append(m.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: parts[len(parts)-1]}, &val)
+	}
+	return root, nil
+}
+
+func childMapping(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(m.Content); i += 2 {
+		if m.Content[i].Value == key && m.Content[i+1].Kind == yaml.MappingNode {
+			return m.Content[i+1]
+		}
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+	return child
+}
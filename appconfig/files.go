@@ -0,0 +1,259 @@
+package appconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// profileFlag names the flag that selects the config overlay of an
+	// environment, see configFiles.
+	profileFlag = "profile"
+	// profileEnv is the env var of profileFlag. It does not follow the
+	// key → env var mapping because PROFILE is too generic a name.
+	profileEnv = "APP_PROFILE"
+)
+
+// configFiles returns the config files to merge, in order: the
+// comma-separated list of --config_file, then, with a profile, the overlay
+// next to the first file: config.yaml → config.<profile>.yaml.
+func configFiles(list, profile string) ([]string, error) {
+	var files []string
+	for _, f := range splitList(list) {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	if profile == "" {
+		return files, nil
+	}
+	if len(files) == 0 {
+		return nil, configError(fmt.Sprintf("--%s %s needs a base file in --config_file", profileFlag, profile))
+	}
+	return append(files, profileFile(files[0], profile)), nil
+}
+
+// profileFile returns the overlay of base for profile.
+func profileFile(base, profile string) string {
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + profile + ext
+}
+
+// readConfigFiles rebuilds the config layer from l.files: the first file,
+// deep-merged with each overlay in order. Overlays are read and checked
+// first, so the config layer is left untouched when any file fails. The
+// caller holds l.mu once the Loader is shared.
+func (l *Loader) readConfigFiles() error {
+	overlays := make([]map[string]any, 0, len(l.files)-1)
+	for _, f := range l.files[1:] {
+		m, err := readConfigFile(f)
+		if err != nil {
+			return err
+		}
+		if err := l.keys.check(f, m); err != nil {
+			return err
+		}
+		overlays = append(overlays, m)
+	}
+
+	l.v.SetConfigFile(l.files[0])
+	if err := l.v.ReadInConfig(); err != nil {
+		return fmt.Errorf("appconfig: read config file %q: %w", l.files[0], err)
+	}
+	for i, m := range overlays {
+		if err := l.v.MergeConfigMap(m); err != nil {
+			return fmt.Errorf("appconfig: merge config file %q: %w", l.files[i+1], err)
+		}
+	}
+	return nil
+}
+
+func readConfigFile(path string) (map[string]any, error) {
+	fv := viper.New()
+	fv.SetConfigFile(path)
+	if err := fv.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("appconfig: read config file %q: %w", path, err)
+	}
+	return fv.AllSettings(), nil
+}
+
+// reloadFiles re-reads the config files and re-applies the last remote
+// document on top. A failure keeps the current config layer and is returned
+// by Unmarshal until a later reload succeeds.
+func (l *Loader) reloadFiles() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.filesErr = l.readConfigFiles()
+	if l.filesErr == nil && l.remoteSettings != nil {
+		_ = l.v.MergeConfigMap(l.remoteSettings)
+	}
+}
+
+// watchFiles watches the directories of the config files and calls fn after
+// any of them was written, created or, for symlinked files such as
+// Kubernetes ConfigMap mounts, re-pointed.
+func (l *Loader) watchFiles(fn func(fsnotify.Event)) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	real := make(map[string]string, len(l.files))
+	dirs := make(map[string]bool)
+	for _, f := range l.files {
+		f = filepath.Clean(f)
+		real[f], _ = filepath.EvalSymlinks(f)
+		dirs[filepath.Dir(f)] = true
+	}
+	for dir := range dirs {
+		if err := w.Add(dir); err != nil {
+			_ = w.Close()
+			return err
+		}
+	}
+
+	go func() {
+		defer w.Close()
+		for {
+			select {
+			case e, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if filesChanged(e, real) {
+					l.reloadFiles()
+					fn(e)
+				}
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// filesChanged reports whether e changed one of the files, keyed by their
+// cleaned path with the resolved symlink target as value, and records new
+// symlink targets.
+func filesChanged(e fsnotify.Event, real map[string]string) bool {
+	changed := false
+	name := filepath.Clean(e.Name)
+	for f, target := range real {
+		cur, _ := filepath.EvalSymlinks(f)
+		if name == f && e.Op&(fsnotify.Write|fsnotify.Create) != 0 || cur != "" && cur != target {
+			real[f] = cur
+			changed = true
+		}
+	}
+	return changed
+}
+
+// keySet is the set of config keys defined by the flags and the config
+// struct. Keys below a map or interface field are open: any sub-key is
+// accepted.
+type keySet struct {
+	exact map[string]bool
+	open  map[string]bool
+}
+
+// configKeys collects the keys of the flags in fs and the fields of target.
+// It returns nil, which accepts every key, when target is not a struct.
+func configKeys(fs *pflag.FlagSet, target any) *keySet {
+	t := reflect.TypeOf(target)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	ks := &keySet{exact: map[string]bool{}, open: map[string]bool{}}
+	ks.addFields(t, "")
+	fs.VisitAll(func(f *pflag.Flag) { ks.exact[f.Name] = true })
+	return ks
+}
+
+func (ks *keySet) addFields(t reflect.Type, prefix string) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, squash := mapstructureKey(sf)
+		if name == "-" {
+			continue
+		}
+		key := prefix
+		if !squash {
+			key = joinKey(prefix, name)
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft.Kind() == reflect.Struct && ft != reflect.TypeFor[time.Time]():
+			ks.addFields(ft, key)
+		case ft.Kind() == reflect.Map || ft.Kind() == reflect.Interface:
+			ks.exact[key] = true
+			ks.open[key] = true
+		default:
+			ks.exact[key] = true
+		}
+	}
+}
+
+func (ks *keySet) has(key string) bool {
+	if ks.exact[key] {
+		return true
+	}
+	for i := range len(key) {
+		if key[i] == '.' && ks.open[key[:i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns a config error listing the keys of the overlay file that no
+// section or config struct defines, most likely typos that would otherwise
+// be ignored silently.
+func (ks *keySet) check(file string, settings map[string]any) error {
+	if ks == nil {
+		return nil
+	}
+	var unknown []string
+	for _, key := range settingKeys(settings, "") {
+		if !ks.has(key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return configError(fmt.Sprintf("config file %q sets keys that no section or config struct defines: %s",
+		file, strings.Join(unknown, ", ")))
+}
+
+// settingKeys returns the dotted keys of the leaves of the nested map m.
+func settingKeys(m map[string]any, prefix string) []string {
+	var keys []string
+	for k, v := range m {
+		key := joinKey(prefix, k)
+		if sub, ok := v.(map[string]any); ok {
+			keys = append(keys, settingKeys(sub, key)...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -7,16 +7,20 @@ import (
 
 // Postgres holds PostgreSQL connection and connection-pool configuration.
 //
-// Env vars: POSTGRES_DSN, POSTGRES_MAX_OPEN_CONNS, POSTGRES_MAX_IDLE_CONNS,
-// POSTGRES_CONN_MAX_IDLE_TIME, POSTGRES_CONN_MAX_LIFETIME.
+// Env vars: POSTGRES_DSN, POSTGRES_STANDBY_DSNS, POSTGRES_MAX_OPEN_CONNS,
+// POSTGRES_MAX_IDLE_CONNS, POSTGRES_CONN_MAX_IDLE_TIME,
+// POSTGRES_CONN_MAX_LIFETIME.
 type Postgres struct {
-	DSN string `mapstructure:"dsn"`
+	DSN string `mapstructure:"dsn" validate:"required"`
+	// StandbyDSNs are read-only replicas, e.g. for pgw.StandbyConfig.
+	// Env: comma-separated list.
+	StandbyDSNs []string `mapstructure:"standby_dsns"`
 
 	// Connection pool — zero values mean "use driver default".
-	MaxOpenConns    int           `mapstructure:"max_open_conns"`
-	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
-	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
-	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	MaxOpenConns    int           `mapstructure:"max_open_conns" validate:"min=0"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns" validate:"min=0"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time" validate:"min=0s"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime" validate:"min=0s"`
 }
 
 // ApplyToSQLDB applies non-zero pool settings to a *sql.DB.
@@ -1,9 +1,24 @@
 package appconfig
 
 // Redis holds Redis connection configuration.
-// Env vars: REDIS_ADDR, REDIS_PASSWORD, REDIS_DB.
+// Env vars: REDIS_ADDR, REDIS_USERNAME, REDIS_PASSWORD, REDIS_DB,
+// REDIS_SENTINEL_MASTER, REDIS_SENTINEL_ADDRS, REDIS_SENTINEL_PASSWORD,
+// REDIS_TLS_*.
+//
+// When Sentinel.Master is set, Addr is ignored and the master is discovered
+// through Sentinel.Addrs.
 type Redis struct {
-	Addr     string `mapstructure:"addr"`
-	Password string `mapstructure:"password"`
-	DB       int    `mapstructure:"db"`
+	Addr     string        `mapstructure:"addr"`
+	Username string        `mapstructure:"username"`
+	Password string        `mapstructure:"password"`
+	DB       int           `mapstructure:"db" validate:"min=0"`
+	Sentinel RedisSentinel `mapstructure:"sentinel"`
+	TLS      ClientTLS     `mapstructure:"tls"`
+}
+
+// RedisSentinel holds Redis Sentinel configuration.
+type RedisSentinel struct {
+	Master   string   `mapstructure:"master"`
+	Addrs    []string `mapstructure:"addrs"`
+	Password string   `mapstructure:"password"`
 }
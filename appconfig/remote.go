@@ -0,0 +1,246 @@
+package appconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ErrKeyNotFound is returned, wrapped, by KVSource.GetFromKV when the key
+// does not exist.
+var ErrKeyNotFound = errors.New("appconfig: remote key not found")
+
+// KVSource reads and watches a single key of a remote key/value store.
+// GetFromKV returns an error wrapping ErrKeyNotFound for a missing key.
+// Build one from a discovery.KVProvider with KVFrom.
+type KVSource interface {
+	GetFromKV(ctx context.Context, key string) ([]byte, error)
+	WatchKV(ctx context.Context, key string) KVWatcher
+}
+
+// KVWatcher yields the value of a watched key each time it changes.
+// Next returns a nil value when the key is deleted. It has the same method
+// set as discovery.KVWatcher.
+type KVWatcher interface {
+	Next() ([]byte, error)
+	Stop() error
+}
+
+// KVFrom adapts the GetFromKV and GetKVWatcher methods of a
+// discovery.KVProvider to a KVSource. Errors of get that match one of
+// notFound are reported as ErrKeyNotFound:
+//
+//	kv := registry.KV()
+//	src := appconfig.KVFrom(kv.GetFromKV, kv.GetKVWatcher, discovery.ErrKeyNotFound)
+func KVFrom[W KVWatcher](
+	get func(ctx context.Context, key string) ([]byte, error),
+	watch func(ctx context.Context, key string) W,
+	notFound ...error,
+) KVSource {
+	return kvFuncs[W]{get: get, watch: watch, notFound: notFound}
+}
+
+type kvFuncs[W KVWatcher] struct {
+	get      func(ctx context.Context, key string) ([]byte, error)
+	watch    func(ctx context.Context, key string) W
+	notFound []error
+}
+
+func (f kvFuncs[W]) GetFromKV(ctx context.Context, key string) ([]byte, error) {
+	v, err := f.get(ctx, key)
+	for _, target := range f.notFound {
+		if errors.Is(err, target) {
+			return nil, fmt.Errorf("%w: %w", ErrKeyNotFound, err)
+		}
+	}
+	return v, err
+}
+
+func (f kvFuncs[W]) WatchKV(ctx context.Context, key string) KVWatcher {
+	return f.watch(ctx, key)
+}
+
+// Remote configures a remote configuration source: one KV key holding a
+// config document in the same shape as the config file. Remote values
+// override the config file and are overridden by env vars and flags.
+//
+// The whole config lives in that single document rather than in one KV key
+// per config key, since discovery.KVProvider can only read and watch single
+// keys. A missing key is an empty remote layer.
+type Remote struct {
+	// KV is the store to read from, e.g. Consul KV via KVFrom. Required.
+	KV KVSource
+	// DocumentKey is the KV key of the config document, e.g.
+	// "config/im-gateway". Required.
+	DocumentKey string
+	// Format is the document format understood by viper ("yaml", "json",
+	// "toml", ...). Default: yaml.
+	Format string
+	// CacheFile keeps a copy of the last document read from KV. When set,
+	// Load falls back to it if KV is unreachable, so the service can boot
+	// while Consul is down. Written with 0600 permissions.
+	CacheFile string
+	// Timeout bounds the initial read in Load. Default: 5s.
+	Timeout time.Duration
+	// RetryInterval is the pause after a failed watch before it is resumed.
+	// Default: 5s.
+	RetryInterval time.Duration
+	// OnError, when set, receives errors that do not fail Load or stop
+	// WatchRemote: a KV read served from CacheFile, a failed cache write,
+	// a watch failure, or an unparsable document.
+	OnError func(error)
+}
+
+// SetRemote layers a remote source between env vars and the config file, so
+// the precedence becomes flag > env > remote > config file > default.
+// Call before Load.
+func (l *Loader) SetRemote(r Remote) {
+	if r.Format == "" {
+		r.Format = "yaml"
+	}
+	if r.Timeout <= 0 {
+		r.Timeout = 5 * time.Second
+	}
+	if r.RetryInterval <= 0 {
+		r.RetryInterval = 5 * time.Second
+	}
+	l.remote = &r
+}
+
+// WatchRemote watches the remote key and calls fn after every change has been
+// applied; re-unmarshal the config in fn. Changes that fail to parse are
+// reported to Remote.OnError and skipped, and a deleted key keeps the last
+// applied document. The watch runs in its own goroutine until ctx is done.
+func (l *Loader) WatchRemote(ctx context.Context, fn func()) error {
+	r := l.remote
+	if r == nil {
+		return errors.New("appconfig: no remote source, call SetRemote first")
+	}
+	go func() {
+		w := r.KV.WatchKV(ctx, r.DocumentKey)
+		defer w.Stop()
+		for {
+			doc, err := w.Next()
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				l.remoteError(fmt.Errorf("appconfig: watch remote config %q: %w", r.DocumentKey, err))
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(r.RetryInterval):
+				}
+				continue
+			}
+			if doc == nil {
+				continue
+			}
+			if err := l.applyRemote(doc); err != nil {
+				l.remoteError(err)
+				continue
+			}
+			l.writeRemoteCache(doc)
+			fn()
+		}
+	}()
+	return nil
+}
+
+// loadRemote reads the remote document, falling back to Remote.CacheFile, and
+// merges it over the config file. A missing key leaves the remote layer
+// empty.
+func (l *Loader) loadRemote() error {
+	r := l.remote
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	doc, err := r.KV.GetFromKV(ctx, r.DocumentKey)
+	cancel()
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		err = fmt.Errorf("appconfig: read remote config %q: %w", r.DocumentKey, err)
+		if r.CacheFile == "" {
+			return err
+		}
+		cached, cerr := os.ReadFile(r.CacheFile)
+		if cerr != nil {
+			return errors.Join(err, fmt.Errorf("appconfig: read remote config cache: %w", cerr))
+		}
+		l.remoteError(fmt.Errorf("%w; using cache %q", err, r.CacheFile))
+		return l.applyRemote(cached)
+	}
+	if err := l.applyRemote(doc); err != nil {
+		return err
+	}
+	l.writeRemoteCache(doc)
+	return nil
+}
+
+// applyRemote parses doc and rebuilds the config layer as the config files
+// (if any) overlaid with doc. The config layer is left untouched when doc does
+// not parse.
+func (l *Loader) applyRemote(doc []byte) error {
+	settings, err := l.parseRemote(doc)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.files) > 0 {
+		if err := l.readConfigFiles(); err != nil {
+			return err
+		}
+	}
+	l.remoteSettings = settings
+	return l.v.MergeConfigMap(settings)
+}
+
+func (l *Loader) parseRemote(doc []byte) (map[string]any, error) {
+	rv := viper.New()
+	rv.SetConfigType(l.remote.Format)
+	if err := rv.ReadConfig(bytes.NewReader(doc)); err != nil {
+		return nil, fmt.Errorf("appconfig: parse remote config %q: %w", l.remote.DocumentKey, err)
+	}
+	return rv.AllSettings(), nil
+}
+
+// writeRemoteCache atomically replaces Remote.CacheFile with doc.
+func (l *Loader) writeRemoteCache(doc []byte) {
+	path := l.remote.CacheFile
+	if path == "" {
+		return
+	}
+	if err := writeFileAtomic(path, doc); err != nil {
+		l.remoteError(fmt.Errorf("appconfig: write remote config cache: %w", err))
+	}
+}
+
+func (l *Loader) remoteError(err error) {
+	if l.remote.OnError != nil {
+		l.remote.OnError(err)
+	}
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,291 @@
+package appconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/pflag"
+)
+
+// durationPattern matches the time.ParseDuration syntax.
+const durationPattern = `^[-+]?(\d+(\.\d*)?(ns|us|µs|ms|s|m|h))+$|^0$`
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing target, the
+// service config struct, for validating config files and Helm values.
+// Keys follow the mapstructure tags; built-in sections that are not enabled
+// are left out. Flag defaults and usage strings become "default" and
+// "description", and validate tags become the matching constraints.
+//
+// The schema covers the file layer only. Any key may come from a flag, an
+// env var or a secret reference instead, so "required" is not exported, and,
+// as in Validate, the other constraints admit the zero value.
+func (l *Loader) JSONSchema(target any) ([]byte, error) {
+	t := reflect.TypeOf(target)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("appconfig: JSONSchema: want a struct, got %T", target)
+	}
+
+	// Flags of the built-in sections, plus the service flags registered on
+	// the FlagSet passed to Load.
+	flags := pflag.NewFlagSet("schema", pflag.ContinueOnError)
+	l.RegisterFlags(flags)
+	if l.fs != nil {
+		l.fs.VisitAll(func(f *pflag.Flag) {
+			if flags.Lookup(f.Name) == nil {
+				flags.AddFlag(f)
+			}
+		})
+	}
+
+	sc := schemaBuilder{flags: flags, skip: l.sections.disabled()}
+	schema, err := sc.structSchema(t, "")
+	if err != nil {
+		return nil, err
+	}
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+type schemaBuilder struct {
+	flags *pflag.FlagSet
+	skip  map[string]bool
+}
+
+func (b schemaBuilder) structSchema(t reflect.Type, prefix string) (map[string]any, error) {
+	props := map[string]any{}
+	if err := b.addFields(t, prefix, props); err != nil {
+		return nil, err
+	}
+	return map[string]any{"type": "object", "properties": props}, nil
+}
+
+func (b schemaBuilder) addFields(t reflect.Type, prefix string, props map[string]any) error {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, squash := mapstructureKey(sf)
+		if name == "-" {
+			continue
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if squash && ft.Kind() == reflect.Struct {
+			if err := b.addFields(ft, prefix, props); err != nil {
+				return err
+			}
+			continue
+		}
+		key := joinKey(prefix, name)
+		if prefix == "" && b.skip[key] {
+			continue
+		}
+
+		schema, err := b.typeSchema(ft, key)
+		if err != nil {
+			return err
+		}
+		if f := b.flags.Lookup(key); f != nil {
+			schema["description"] = f.Usage
+			if def, ok := flagDefault(f, ft); ok {
+				schema["default"] = def
+			}
+		}
+		if rules, ok := sf.Tag.Lookup("validate"); ok {
+			if err := applyRules(schema, ft, rules); err != nil {
+				return fmt.Errorf("appconfig: %s: %w", key, err)
+			}
+		}
+		props[name] = schema
+	}
+	return nil
+}
+
+func (b schemaBuilder) typeSchema(t reflect.Type, key string) (map[string]any, error) {
+	if t == durationType {
+		return map[string]any{"type": "string", "pattern": durationPattern}, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := b.typeSchema(elem(t), key)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("appconfig: %s: map key must be a string, got %s", key, t.Key())
+		}
+		values, err := b.typeSchema(elem(t), key)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		return b.structSchema(t, key)
+	case reflect.Interface:
+		return map[string]any{}, nil
+	}
+	return nil, fmt.Errorf("appconfig: %s: unsupported type %s", key, t)
+}
+
+func elem(t reflect.Type) reflect.Type {
+	t = t.Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// applyRules maps validate rules to schema constraints. "required" is left
+// out, see JSONSchema. Validate skips the other rules on zero values, so on
+// a scalar the constraints become one branch of an anyOf, next to the zero
+// value; an empty list or map is not a zero value and is checked as is.
+func applyRules(schema map[string]any, t reflect.Type, rules string) error {
+	constraints := map[string]any{}
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "", "required":
+		case "min", "max":
+			if t == durationType {
+				continue // not expressible on duration strings
+			}
+			kw, n, err := boundKeyword(t, name, arg)
+			if err != nil {
+				return err
+			}
+			constraints[kw] = n
+		case "oneof":
+			if t.Kind() == reflect.String {
+				// enum is case-sensitive, unlike the oneof check.
+				constraints["pattern"] = foldPattern(strings.Fields(arg))
+				schema["examples"] = strings.Fields(arg)
+				continue
+			}
+			var enum []any
+			for _, o := range strings.Fields(arg) {
+				v, err := typedValue(t, o)
+				if err != nil {
+					return err
+				}
+				enum = append(enum, v)
+			}
+			constraints["enum"] = enum
+		case "url":
+			constraints["format"] = "uri"
+		case "file":
+		default:
+			return fmt.Errorf("unknown validate rule %q", name)
+		}
+	}
+	if len(constraints) == 0 {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		maps.Copy(schema, constraints)
+	default:
+		zero := "0"
+		if t.Kind() == reflect.String {
+			zero = ""
+		}
+		v, _ := typedValue(t, zero)
+		schema["anyOf"] = []any{map[string]any{"const": v}, constraints}
+	}
+	return nil
+}
+
+func boundKeyword(t reflect.Type, name, arg string) (string, any, error) {
+	n, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid %s bound %q", name, arg)
+	}
+	var kw string
+	switch t.Kind() {
+	case reflect.String:
+		kw = map[string]string{"min": "minLength", "max": "maxLength"}[name]
+	case reflect.Slice, reflect.Array:
+		kw = map[string]string{"min": "minItems", "max": "maxItems"}[name]
+	case reflect.Map:
+		kw = map[string]string{"min": "minProperties", "max": "maxProperties"}[name]
+	default:
+		kw = map[string]string{"min": "minimum", "max": "maximum"}[name]
+	}
+	return kw, n, nil
+}
+
+// flagDefault converts the default of f to the JSON type of t.
+func flagDefault(f *pflag.Flag, t reflect.Type) (any, bool) {
+	if t == durationType {
+		return f.DefValue, true
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		return nil, false
+	}
+	v, err := typedValue(t, f.DefValue)
+	return v, err == nil
+}
+
+func typedValue(t reflect.Type, s string) (any, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t == durationType {
+			return s, nil
+		}
+		return strconv.ParseInt(s, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(s, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(s, 64)
+	}
+	return s, nil
+}
+
+// foldPattern returns a pattern matching any of opts regardless of case.
+// The schema regex dialect has no case-insensitive flag, so every letter
+// becomes a class of both cases.
+func foldPattern(opts []string) string {
+	var b strings.Builder
+	b.WriteString("^(")
+	for i, o := range opts {
+		if i > 0 {
+			b.WriteByte('|')
+		}
+		for _, r := range o {
+			lower, upper := unicode.ToLower(r), unicode.ToUpper(r)
+			if lower == upper {
+				b.WriteString(regexp.QuoteMeta(string(r)))
+				continue
+			}
+			b.WriteString("[" + string(lower) + string(upper) + "]")
+		}
+	}
+	b.WriteString(")$")
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package appconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// SecretProvider resolves secret references of one scheme. ref is the part
+// after "<scheme>://", e.g. "/run/secrets/pg" for "file:///run/secrets/pg".
+type SecretProvider interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to SecretProvider.
+type SecretProviderFunc func(ctx context.Context, ref string) (string, error)
+
+func (f SecretProviderFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// FileSecret reads the secret from the file at ref, without trailing newlines.
+// Registered for the "file" scheme by default.
+var FileSecret SecretProvider = SecretProviderFunc(func(_ context.Context, ref string) (string, error) {
+	return readSecretFile(ref)
+})
+
+// EnvSecret reads the secret from the env var named ref.
+// Registered for the "env" scheme by default.
+var EnvSecret SecretProvider = SecretProviderFunc(func(_ context.Context, ref string) (string, error) {
+	v, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("env var %s is not set", ref)
+	}
+	return v, nil
+})
+
+// RegisterSecretProvider resolves string values of the form "<scheme>://ref"
+// with p, e.g. a Vault backend for "vault://". It replaces the provider of a
+// scheme registered earlier, including the built-in "file" and "env".
+// Values with other schemes, such as DSNs and URLs, are left as is.
+func (l *Loader) RegisterSecretProvider(scheme string, p SecretProvider) {
+	l.secretProviders[scheme] = p
+}
+
+// IsSecret reports whether the value of key (e.g. "postgres.dsn") came from
+// a secret reference or a *_FILE env var in the last Load or Unmarshal.
+// Dumps and logs of the config should redact such values.
+func (l *Loader) IsSecret(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.secrets[strings.ToLower(key)]
+}
+
+// SecretKeys returns the sorted keys whose values are secrets, see IsSecret.
+func (l *Loader) SecretKeys() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	keys := make([]string, 0, len(l.secrets))
+	for k := range l.secrets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolveSecrets replaces secret references in settings, the nested map from
+// viper.AllSettings, and records the resolved keys.
+//
+// A key is resolved from the file named by its env var with a _FILE suffix
+// (POSTGRES_DSN_FILE for postgres.dsn) unless the flag or the env var itself
+// is set; otherwise a string value "<scheme>://ref" with a registered scheme
+// is resolved by that scheme's provider.
+func (l *Loader) resolveSecrets(ctx context.Context, settings map[string]any) error {
+	secrets := make(map[string]bool)
+	err := l.resolveSecretsIn(ctx, settings, "", secrets)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.secrets = secrets
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *Loader) resolveSecretsIn(ctx context.Context, m map[string]any, prefix string, secrets map[string]bool) error {
+	for k, v := range m {
+		key := joinKey(prefix, k)
+		if sub, ok := v.(map[string]any); ok {
+			if err := l.resolveSecretsIn(ctx, sub, key, secrets); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if path, ok := l.secretFileEnv(key); ok {
+			s, err := readSecretFile(path)
+			if err != nil {
+				return fmt.Errorf("appconfig: resolve secret %s from %s_FILE: %w", key, envName(key), err)
+			}
+			m[k] = s
+			secrets[key] = true
+			continue
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		scheme, ref, ok := strings.Cut(s, "://")
+		if !ok {
+			continue
+		}
+		p, ok := l.secretProviders[scheme]
+		if !ok {
+			continue
+		}
+		resolved, err := p.Resolve(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("appconfig: resolve secret %s (%s://): %w", key, scheme, err)
+		}
+		m[k] = resolved
+		secrets[key] = true
+	}
+	return nil
+}
+
+// secretFileEnv returns the path in the *_FILE env var of key, when neither
+// its flag nor its own env var is set.
+func (l *Loader) secretFileEnv(key string) (string, bool) {
+	env := envName(key)
+	path, ok := os.LookupEnv(env + "_FILE")
+	if !ok || path == "" {
+		return "", false
+	}
+	if _, set := os.LookupEnv(env); set {
+		return "", false
+	}
+	if l.fs != nil {
+		if f := l.fs.Lookup(key); f != nil && f.Changed {
+			return "", false
+		}
+	}
+	return path, true
+}
+
+func readSecretFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// decodeSettings unmarshals settings into target with viper's decoder, so
+// durations, slices and weak typing behave exactly like viper.Unmarshal.
+func decodeSettings(settings map[string]any, target any) error {
+	v := viper.New()
+	if err := v.MergeConfigMap(settings); err != nil {
+		return err
+	}
+	return v.Unmarshal(target)
+}
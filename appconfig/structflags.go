@@ -0,0 +1,301 @@
+package appconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// RegisterStruct registers a flag for every field of cfg, a pointer to the
+// service config struct, so service settings need no hand-written pflag
+// calls. Call it next to RegisterFlags, before pflag.Parse():
+//
+//	type ServiceConfig struct {
+//	    ID       string        `mapstructure:"id"       usage:"Service ID"`
+//	    Workers  int           `mapstructure:"workers"  default:"4" usage:"Worker count"`
+//	    Timeout  time.Duration `mapstructure:"timeout"  default:"5s"`
+//	    Peers    []string      `mapstructure:"peers"    default:"a:1,b:2"`
+//	    Internal string        `mapstructure:"internal" flag:"-"`
+//	}
+//
+// Flag names are the mapstructure key paths ("service.workers"), so they
+// always match the env vars (SERVICE_WORKERS) and config file keys. Nested
+// structs are walked; squashed fields keep their parent's prefix.
+//
+// Tags:
+//
+//	default  the default value, in flag syntax (comma-separated for slices,
+//	         k=v,k2=v2 for map[string]string)
+//	usage    the flag help text
+//	flag:"-" registers no flag; the key still takes env vars, the config
+//	         file and the default
+//
+// Supported field kinds: string, bool, ints, uints, floats, time.Duration,
+// slices of string, int, uint, float64, bool and time.Duration, and
+// map[string]string. Top-level keys of the built-in sections (log,
+// postgres, ...) are left to RegisterFlags, and flags that are already
+// defined are kept.
+func (l *Loader) RegisterStruct(fs *pflag.FlagSet, cfg any) error {
+	t := reflect.TypeOf(cfg)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("appconfig: RegisterStruct: want a pointer to a struct, got %T", cfg)
+	}
+	return l.registerFields(fs, t, "")
+}
+
+func (l *Loader) registerFields(fs *pflag.FlagSet, t reflect.Type, prefix string) error {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, squash := mapstructureKey(sf)
+		if name == "-" {
+			continue
+		}
+		key := prefix
+		if !squash {
+			key = joinKey(prefix, name)
+		}
+		if prefix == "" {
+			if _, builtin := l.sections.disabled()[key]; builtin {
+				continue
+			}
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeFor[time.Time]() {
+			if err := l.registerFields(fs, ft, key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		def, hasDef := sf.Tag.Lookup("default")
+		if sf.Tag.Get("flag") == "-" {
+			if hasDef {
+				v, err := parseDefault(ft, def)
+				if err != nil {
+					return fmt.Errorf("appconfig: %s: default %q: %w", key, def, err)
+				}
+				l.v.SetDefault(key, v)
+			} else {
+				l.v.SetDefault(key, reflect.Zero(ft).Interface())
+			}
+			continue
+		}
+		if fs.Lookup(key) != nil {
+			continue
+		}
+		if err := addFlag(fs, key, ft, def, sf.Tag.Get("usage")); err != nil {
+			return fmt.Errorf("appconfig: %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// addFlag defines a pflag of the type matching t, with def parsed as its
+// default.
+func addFlag(fs *pflag.FlagSet, name string, t reflect.Type, def, usage string) error {
+	v, err := parseDefault(t, def)
+	if err != nil {
+		return fmt.Errorf("default %q: %w", def, err)
+	}
+	switch v := v.(type) {
+	case string:
+		fs.String(name, v, usage)
+	case bool:
+		fs.Bool(name, v, usage)
+	case int:
+		fs.Int(name, v, usage)
+	case int8:
+		fs.Int8(name, v, usage)
+	case int16:
+		fs.Int16(name, v, usage)
+	case int32:
+		fs.Int32(name, v, usage)
+	case int64:
+		fs.Int64(name, v, usage)
+	case uint:
+		fs.Uint(name, v, usage)
+	case uint8:
+		fs.Uint8(name, v, usage)
+	case uint16:
+		fs.Uint16(name, v, usage)
+	case uint32:
+		fs.Uint32(name, v, usage)
+	case uint64:
+		fs.Uint64(name, v, usage)
+	case float32:
+		fs.Float32(name, v, usage)
+	case float64:
+		fs.Float64(name, v, usage)
+	case time.Duration:
+		fs.Duration(name, v, usage)
+	case []string:
+		fs.StringSlice(name, v, usage)
+	case []int:
+		fs.IntSlice(name, v, usage)
+	case []uint:
+		fs.UintSlice(name, v, usage)
+	case []float64:
+		fs.Float64Slice(name, v, usage)
+	case []bool:
+		fs.BoolSlice(name, v, usage)
+	case []time.Duration:
+		fs.DurationSlice(name, v, usage)
+	case map[string]string:
+		fs.StringToString(name, v, usage)
+	default:
+		return fmt.Errorf("unsupported type %s", t)
+	}
+	return nil
+}
+
+// parseDefault parses s into the flag value type for t. An empty s yields
+// the zero value.
+func parseDefault(t reflect.Type, s string) (any, error) {
+	if t == durationType {
+		if s == "" {
+			return time.Duration(0), nil
+		}
+		return time.ParseDuration(s)
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return s, nil
+	case reflect.Bool:
+		if s == "" {
+			return false, nil
+		}
+		return strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := parseNumber(s, func(s string) (int64, error) { return strconv.ParseInt(s, 10, t.Bits()) })
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(n).Convert(kindType(t.Kind())).Interface(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := parseNumber(s, func(s string) (uint64, error) { return strconv.ParseUint(s, 10, t.Bits()) })
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(n).Convert(kindType(t.Kind())).Interface(), nil
+	case reflect.Float32, reflect.Float64:
+		n, err := parseNumber(s, func(s string) (float64, error) { return strconv.ParseFloat(s, t.Bits()) })
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(n).Convert(kindType(t.Kind())).Interface(), nil
+	case reflect.Slice:
+		return parseSliceDefault(t.Elem(), s)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String || t.Elem().Kind() != reflect.String {
+			break
+		}
+		m := map[string]string{}
+		for _, kv := range splitList(s) {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, fmt.Errorf("%q is not k=v", kv)
+			}
+			m[k] = v
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+func parseSliceDefault(elem reflect.Type, s string) (any, error) {
+	items := splitList(s)
+	switch {
+	case elem == durationType:
+		return parseEach(items, time.ParseDuration)
+	case elem.Kind() == reflect.String:
+		return items, nil
+	case elem.Kind() == reflect.Int:
+		return parseEach(items, strconv.Atoi)
+	case elem.Kind() == reflect.Uint:
+		return parseEach(items, func(s string) (uint, error) {
+			n, err := strconv.ParseUint(s, 10, 0)
+			return uint(n), err
+		})
+	case elem.Kind() == reflect.Float64:
+		return parseEach(items, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+	case elem.Kind() == reflect.Bool:
+		return parseEach(items, strconv.ParseBool)
+	}
+	return nil, fmt.Errorf("unsupported slice element type %s", elem)
+}
+
+func parseEach[T any](items []string, parse func(string) (T, error)) ([]T, error) {
+	out := make([]T, 0, len(items))
+	for _, s := range items {
+		v, err := parse(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func parseNumber[T any](s string, parse func(string) (T, error)) (T, error) {
+	if s == "" {
+		var zero T
+		return zero, nil
+	}
+	return parse(s)
+}
+
+// splitList splits a comma-separated default; empty yields no items.
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return []string{}
+	}
+	items := strings.Split(s, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+// kindType returns the predeclared type of a numeric kind, so named types
+// (type Port int) register as the matching pflag type.
+func kindType(k reflect.Kind) reflect.Type {
+	switch k {
+	case reflect.Int:
+		return reflect.TypeFor[int]()
+	case reflect.Int8:
+		return reflect.TypeFor[int8]()
+	case reflect.Int16:
+		return reflect.TypeFor[int16]()
+	case reflect.Int32:
+		return reflect.TypeFor[int32]()
+	case reflect.Int64:
+		return reflect.TypeFor[int64]()
+	case reflect.Uint:
+		return reflect.TypeFor[uint]()
+	case reflect.Uint8:
+		return reflect.TypeFor[uint8]()
+	case reflect.Uint16:
+		return reflect.TypeFor[uint16]()
+	case reflect.Uint32:
+		return reflect.TypeFor[uint32]()
+	case reflect.Uint64:
+		return reflect.TypeFor[uint64]()
+	case reflect.Float32:
+		return reflect.TypeFor[float32]()
+	}
+	return reflect.TypeFor[float64]()
+}
@@ -1,6 +1,13 @@
 package appconfig
 
-import "github.com/spf13/pflag"
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+)
 
 // TLS holds certificate paths for one side of a TLS connection.
 type TLS struct {
@@ -62,3 +69,67 @@ func RegisterGRPCConnFlags(fs *pflag.FlagSet, prefix string, verifyDefault bool)
 	fs.String(prefix+".client.cert", "", "Client certificate path")
 	fs.String(prefix+".client.key", "", "Client certificate key path")
 }
+
+// ClientTLS is the TLS configuration of an outbound client connection, such
+// as Redis or AMQP. TLS is off unless Enabled is set.
+type ClientTLS struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CA                 string `mapstructure:"ca" validate:"file"`
+	Cert               string `mapstructure:"cert" validate:"file"`
+	Key                string `mapstructure:"key" validate:"file"`
+	ServerName         string `mapstructure:"server_name"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+// Config builds a *tls.Config from t, or returns nil when TLS is disabled.
+// CA replaces the system roots; Cert and Key, set together, add a client
+// certificate.
+func (t ClientTLS) Config() (*tls.Config, error) {
+	if !t.Enabled {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	if t.CA != "" {
+		pem, err := os.ReadFile(t.CA)
+		if err != nil {
+			return nil, fmt.Errorf("appconfig: read CA: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("appconfig: no certificates in CA %q", t.CA)
+		}
+	}
+	if (t.Cert == "") != (t.Key == "") {
+		return nil, configError("tls.cert and tls.key must be set together")
+	}
+	if t.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(t.Cert, t.Key)
+		if err != nil {
+			return nil, fmt.Errorf("appconfig: load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// RegisterClientTLSFlags registers ClientTLS flags under the given prefix
+// (e.g. "redis.tls"):
+//
+//	redis.tls.enabled              → REDIS_TLS_ENABLED
+//	redis.tls.ca                   → REDIS_TLS_CA
+//	redis.tls.cert                 → REDIS_TLS_CERT
+//	redis.tls.key                  → REDIS_TLS_KEY
+//	redis.tls.server_name          → REDIS_TLS_SERVER_NAME
+//	redis.tls.insecure_skip_verify → REDIS_TLS_INSECURE_SKIP_VERIFY
+func RegisterClientTLSFlags(fs *pflag.FlagSet, prefix string) {
+	fs.Bool(prefix+".enabled", false, "Connect over TLS")
+	fs.String(prefix+".ca", "", "CA certificate path (default: system roots)")
+	fs.String(prefix+".cert", "", "Client certificate path")
+	fs.String(prefix+".key", "", "Client certificate key path")
+	fs.String(prefix+".server_name", "", "Server name to verify (default: host of the address)")
+	fs.Bool(prefix+".insecure_skip_verify", false, "Skip server certificate verification (testing only)")
+}
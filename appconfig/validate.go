@@ -0,0 +1,288 @@
+package appconfig
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate checks the `validate` struct tags of cfg, a pointer to a config
+// struct, and returns a *ValidationError listing every violation. Field
+// paths follow the mapstructure keys, so they match flag and env names.
+//
+// Rules are comma-separated, e.g. `validate:"required,min=1,max=100"`:
+//
+//	required    value must be non-zero
+//	min=N       numbers: value ≥ N; durations: value ≥ N (e.g. min=1s);
+//	            strings, slices and maps: length ≥ N
+//	max=N       like min, upper bound
+//	oneof=a b   value must be one of the space-separated options; strings
+//	            compare case-insensitively, so WARN matches warn
+//	url         value must be an absolute URL with scheme and host
+//	file        value must name an existing regular file
+//
+// Rules other than required are not checked on zero values; combine them
+// with required to reject empty input. A malformed tag is reported as a
+// plain error, not a ValidationError.
+func Validate(cfg any) error {
+	return validateConfig(cfg, nil)
+}
+
+// validateConfig is Validate skipping the top-level keys in skip.
+func validateConfig(cfg any, skip map[string]bool) error {
+	v := reflect.ValueOf(cfg)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var ve ValidationError
+	if err := validateStruct(v, "", skip, &ve); err != nil {
+		return err
+	}
+	if len(ve.Fields) > 0 {
+		return &ve
+	}
+	return nil
+}
+
+// ValidationError aggregates every invalid field found by Validate.
+// IsConfigError reports true for it.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 1 {
+		return "config: " + e.Fields[0].String()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "config: %d invalid values:", len(e.Fields))
+	for _, f := range e.Fields {
+		b.WriteString("\n  ")
+		b.WriteString(f.String())
+	}
+	return b.String()
+}
+
+// FieldError is a single violation: the field path, the flag and env var
+// that set it, and what is wrong.
+type FieldError struct {
+	Path string // e.g. "postgres.dsn"
+	Flag string // e.g. "--postgres.dsn"
+	Env  string // e.g. "POSTGRES_DSN"
+	Rule string // the failed rule, e.g. "required"
+	Msg  string // e.g. "is required"
+}
+
+func (f FieldError) String() string {
+	return fmt.Sprintf("%s (%s, %s): %s", f.Path, f.Flag, f.Env, f.Msg)
+}
+
+func validateStruct(v reflect.Value, prefix string, skip map[string]bool, ve *ValidationError) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, squash := mapstructureKey(sf)
+		if name == "-" {
+			continue
+		}
+		path := prefix
+		if !squash {
+			path = joinKey(prefix, name)
+		}
+		if prefix == "" && skip[path] {
+			continue
+		}
+
+		fv := v.Field(i)
+		if rules, ok := sf.Tag.Lookup("validate"); ok {
+			if err := checkField(fv, path, rules, ve); err != nil {
+				return err
+			}
+		}
+
+		if fv.Kind() == reflect.Pointer && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct && fv.Type() != reflect.TypeFor[time.Time]() {
+			if err := validateStruct(fv, path, skip, ve); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkField(v reflect.Value, path, rules string, ve *ValidationError) error {
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(rule, "=")
+		if name != "required" && v.IsZero() {
+			continue
+		}
+		msg, err := checkRule(v, name, arg)
+		if err != nil {
+			return fmt.Errorf("appconfig: %s: invalid validate rule %q: %w", path, rule, err)
+		}
+		if msg != "" {
+			ve.Fields = append(ve.Fields, FieldError{
+				Path: path,
+				Flag: "--" + path,
+				Env:  envName(path),
+				Rule: name,
+				Msg:  msg,
+			})
+		}
+	}
+	return nil
+}
+
+// checkRule returns a violation message, or "" when v satisfies the rule.
+func checkRule(v reflect.Value, name, arg string) (string, error) {
+	switch name {
+	case "required":
+		if v.IsZero() {
+			return "is required", nil
+		}
+	case "min", "max":
+		c, err := compareTo(v, arg)
+		if err != nil {
+			return "", err
+		}
+		if name == "min" && c < 0 {
+			return boundMsg(v, "at least", arg), nil
+		}
+		if name == "max" && c > 0 {
+			return boundMsg(v, "at most", arg), nil
+		}
+	case "oneof":
+		opts := strings.Fields(arg)
+		if len(opts) == 0 {
+			return "", errors.New("no options")
+		}
+		s := fmt.Sprint(v.Interface())
+		for _, o := range opts {
+			if s == o || v.Kind() == reflect.String && strings.EqualFold(s, o) {
+				return "", nil
+			}
+		}
+		return fmt.Sprintf("must be one of [%s], got %q", strings.Join(opts, " "), s), nil
+	case "url":
+		if v.Kind() != reflect.String {
+			return "", errors.New("url applies to strings only")
+		}
+		u, err := url.Parse(v.String())
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be an absolute URL", nil
+		}
+	case "file":
+		if v.Kind() != reflect.String {
+			return "", errors.New("file applies to strings only")
+		}
+		fi, err := os.Stat(v.String())
+		if err != nil {
+			return fmt.Sprintf("file %q does not exist", v.String()), nil
+		}
+		if !fi.Mode().IsRegular() {
+			return fmt.Sprintf("%q is not a regular file", v.String()), nil
+		}
+	default:
+		return "", errors.New("unknown rule")
+	}
+	return "", nil
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// compareTo compares v (or its length) with the bound arg, returning -1, 0 or 1.
+func compareTo(v reflect.Value, arg string) (int, error) {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(arg)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(v.Int(), int64(d)), nil
+	case v.CanInt():
+		n, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(v.Int(), n), nil
+	case v.CanUint():
+		n, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(v.Uint(), n), nil
+	case v.CanFloat():
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(v.Float(), f), nil
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(v.Len(), n), nil
+	}
+	return 0, fmt.Errorf("not applicable to %s", v.Type())
+}
+
+func boundMsg(v reflect.Value, bound, arg string) string {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return fmt.Sprintf("length must be %s %s, got %d", bound, arg, v.Len())
+	}
+	return fmt.Sprintf("must be %s %s, got %v", bound, arg, v.Interface())
+}
+
+// mapstructureKey returns the key of a struct field as mapstructure decodes
+// it, and whether the field is squashed into its parent.
+func mapstructureKey(sf reflect.StructField) (name string, squash bool) {
+	tag := sf.Tag.Get("mapstructure")
+	name, opts, _ := strings.Cut(tag, ",")
+	for _, o := range strings.Split(opts, ",") {
+		if o == "squash" {
+			squash = true
+		}
+	}
+	if name == "" {
+		name = strings.ToLower(sf.Name)
+	}
+	return name, squash
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// envName derives the env var that sets key, matching the Loader's replacer.
+func envName(key string) string {
+	return strings.ToUpper(envKeyReplacer.Replace(key))
+}
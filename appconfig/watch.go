@@ -0,0 +1,108 @@
+package appconfig
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher holds the current config of type T and replaces it when the
+// config files or the remote source change. Create one with Watch.
+type Watcher[T any] struct {
+	l   *Loader
+	cur atomic.Pointer[T]
+
+	mu      sync.Mutex // serializes reloads and guards the fields below
+	fn      func(old, new T)
+	subs    []func(old, new *T)
+	onError func(error)
+}
+
+// Watch unmarshals the current config into a T and reloads it on every change
+// of the config files (see Loader.Watch) and of the remote source, if set (see
+// Loader.WatchRemote). A reload runs Loader.Unmarshal, secrets and validation
+// included, and is applied only when it succeeds and the result differs from
+// the current config: Current switches to the new value, then fn and the
+// OnSection subscribers are called. A broken file or an invalid value keeps
+// the current config and is reported to OnError.
+//
+// Call after Loader.Load. Watch takes over Loader.Watch and
+// Loader.WatchRemote; do not call them as well. Remote watching stops when
+// ctx is done. An error from either watch is returned, so hot reload never
+// fails silently.
+func Watch[T any](ctx context.Context, l *Loader, fn func(old, new T)) (*Watcher[T], error) {
+	var cur T
+	if err := l.Unmarshal(&cur); err != nil {
+		return nil, err
+	}
+	w := &Watcher[T]{l: l, fn: fn}
+	w.cur.Store(&cur)
+
+	if err := l.Watch(func(fsnotify.Event) { w.reload() }); err != nil {
+		return nil, err
+	}
+	if l.remote != nil {
+		if err := l.WatchRemote(ctx, w.reload); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Current returns the last applied config. Safe for concurrent use.
+func (w *Watcher[T]) Current() T {
+	return *w.cur.Load()
+}
+
+// OnError sets fn to receive reload failures. The current config stays in
+// effect when a reload fails.
+func (w *Watcher[T]) OnError(fn func(error)) {
+	w.mu.Lock()
+	w.onError = fn
+	w.mu.Unlock()
+}
+
+// OnSection subscribes fn to one section of the config, selected by sel, e.g.
+// the log level or the rate limits. fn is called after a reload only when
+// that section changed.
+//
+//	appconfig.OnSection(w, func(c *Config) appconfig.Log { return c.Log },
+//	    func(old, new appconfig.Log) { logger.SetLevel(new.Level) })
+func OnSection[T, S any](w *Watcher[T], sel func(*T) S, fn func(old, new S)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs = append(w.subs, func(old, new *T) {
+		o, n := sel(old), sel(new)
+		if !reflect.DeepEqual(o, n) {
+			fn(o, n)
+		}
+	})
+}
+
+func (w *Watcher[T]) reload() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var next T
+	if err := w.l.Unmarshal(&next); err != nil {
+		if w.onError != nil {
+			w.onError(err)
+		}
+		return
+	}
+	old := w.cur.Load()
+	if reflect.DeepEqual(*old, next) {
+		return
+	}
+	w.cur.Store(&next)
+
+	if w.fn != nil {
+		w.fn(*old, next)
+	}
+	for _, sub := range w.subs {
+		sub(old, &next)
+	}
+}
@@ -2,10 +2,15 @@ package discovery
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 )
 
+// ErrKeyNotFound is returned, wrapped, by KVProvider.GetFromKV when the key
+// does not exist.
+var ErrKeyNotFound = errors.New("not found")
+
 type DiscoveryProvider interface {
 	Registrar
 	Discovery
@@ -31,7 +31,7 @@ func (c *kVClient) DeleteFromKV(ctx context.Context, key string) error {
 
 // GetFromKV returns the value associated with the given key from the Consul KV.
 // If the context is canceled, it will return an error.
-// If the key is not found in the Consul KV, it will return an error.
+// If the key is not found in the Consul KV, it will return an error wrapping discovery.ErrKeyNotFound.
 // If the Get call failed with an error, it will return an error.
 // Otherwise, it will return the value associated with the given key.
 func (c *kVClient) GetFromKV(ctx context.Context, key string) ([]byte, error) {
@@ -41,7 +41,7 @@ func (c *kVClient) GetFromKV(ctx context.Context, key string) ([]byte, error) {
 	}
 
 	if pair == nil {
-		return nil, fmt.Errorf("key '%s' not found in consul kv", key)
+		return nil, fmt.Errorf("key '%s' %w in consul kv", key, discovery.ErrKeyNotFound)
 	}
 
 	return pair.Value, nil
@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/consul/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/webitel/webitel-go-kit/infra/discovery"
 )
 
 func TestGetFromKV(t *testing.T) {
@@ -42,6 +43,7 @@ func TestGetFromKV(t *testing.T) {
 		_, err := kv.GetFromKV(context.Background(), "unknown-key")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
+		assert.ErrorIs(t, err, discovery.ErrKeyNotFound)
 	})
 }
 
@@ -48,6 +48,14 @@ type Registry struct {
 func NewConsulRegistry(addr string, logger discovery.Logger) (*Registry, error) {
 	cfg := api.DefaultConfig()
 	cfg.Address = addr
+	return NewConsulRegistryWithConfig(cfg, logger)
+}
+
+// NewConsulRegistryWithConfig creates a new Consul registry like
+// NewConsulRegistry, with a Consul API client built from cfg, e.g. to set
+// the ACL token or the Consul datacenter of the API calls. Start from
+// api.DefaultConfig, which reads the CONSUL_HTTP_* env vars.
+func NewConsulRegistryWithConfig(cfg *api.Config, logger discovery.Logger) (*Registry, error) {
 	apiClient, err := api.NewClient(cfg)
 	if err != nil {
 		return nil, err
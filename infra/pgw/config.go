@@ -8,6 +8,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/webitel/webitel-go-kit/pkg/safemap"
+	"go.opentelemetry.io/otel/metric"
 )
 
 type Config struct {
@@ -15,16 +16,23 @@ type Config struct {
 
 	Tracer Tracer
 
+	MeterProvider metric.MeterProvider
+
 	PrimaryConfig PrimaryConfig
 
 	StandbyConfig StandbyConfig
 
+	TxConfig TxConfig
+
 	MigrationVerifier MigrationVerifier
 }
 
 type MigrationVerifier func(ctx context.Context, conn *pgxpool.Conn) error
 
 type PrimaryConfig struct {
+	// DSN may list several candidate hosts, e.g.
+	// "postgres://pg1:5432,pg2:5432/db"; the one that is not in recovery
+	// is used, and it is looked up again when the primary fails over.
 	DSN string
 
 	MaxConns int
@@ -54,6 +62,21 @@ type StandbyConfig struct {
 
 	PickStrategy                  PickStrategy
 	UnhealthyReplicaRetryInterval time.Duration
+
+	// MaxLag excludes standbys whose replication lag, sampled by the health
+	// check, exceeds it. Zero disables the check.
+	MaxLag time.Duration
+}
+
+// TxConfig controls how PoolManager.InTx retries transactions that failed
+// with a serialization failure (40001) or a deadlock (40P01).
+type TxConfig struct {
+	// MaxAttempts is the number of runs of a transaction, the first included.
+	MaxAttempts int
+	// Backoff is the delay before the first retry; it doubles with every
+	// further retry, up to MaxBackoff, with jitter.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
 }
 
 type ConfigOption func(*Config)
@@ -135,6 +158,12 @@ var (
 		RetryStrategy:                 RetryStrategyLinear,
 		RetryStrategyBaseValue:        2,
 	}
+
+	DefaultTxConfig = TxConfig{
+		MaxAttempts: 3,
+		Backoff:     50 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
 )
 
 func WithApplicationName(name string) ConfigOption {
@@ -145,6 +174,12 @@ func WithTracer(t Tracer) ConfigOption {
 	return func(c *Config) { c.Tracer = t }
 }
 
+// WithMeterProvider sets the MeterProvider for the pool metrics; without it
+// the global MeterProvider is used.
+func WithMeterProvider(mp metric.MeterProvider) ConfigOption {
+	return func(c *Config) { c.MeterProvider = mp }
+}
+
 func WithPrimaryConfig(cfg PrimaryConfig) ConfigOption {
 	return func(c *Config) { c.PrimaryConfig = cfg }
 }
@@ -153,6 +188,10 @@ func WithStandbyConfig(cfg StandbyConfig) ConfigOption {
 	return func(c *Config) { c.StandbyConfig = cfg }
 }
 
+func WithTxConfig(cfg TxConfig) ConfigOption {
+	return func(c *Config) { c.TxConfig = cfg }
+}
+
 func WithMigrationVerifier(verifier MigrationVerifier) ConfigOption {
 	return func(c *Config) { c.MigrationVerifier = verifier }
 }
@@ -0,0 +1,194 @@
+package pgw
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// notificationBuffer is the capacity of the channel returned by Listen.
+const notificationBuffer = 64
+
+// Notification is a NOTIFY received by PoolManager.Listen, or a gap marker.
+type Notification struct {
+	Channel string
+	Payload string
+	// PID is the backend process that sent the notification.
+	PID uint32
+	// Gap marks that the LISTEN connection was re-established: notifications
+	// sent on Channel while it was down are lost, so the consumer should
+	// resync the state it derives from them. Payload is empty.
+	Gap bool
+}
+
+// Listen subscribes to channels over a dedicated connection to the primary
+// and returns once LISTEN is active. The returned channel receives every
+// notification until ctx is done or the manager is closed; then it is
+// closed.
+//
+// When the connection breaks, Listen waits for the master monitor to
+// reconnect the primary, issues LISTEN again and sends a Notification with
+// Gap set for every channel. The connection is not part of the pool, and a
+// slow consumer holds up delivery rather than losing notifications.
+func (c *PoolManager) Listen(ctx context.Context, channels ...string) (<-chan Notification, error) {
+	if len(channels) == 0 {
+		return nil, errors.New("pgw: at least one notification channel is required")
+	}
+	for _, channel := range channels {
+		if channel == "" {
+			return nil, errors.New("pgw: notification channel must not be empty")
+		}
+	}
+
+	ctx, cancel := c.untilClosed(ctx)
+	conn, err := c.listenConn(ctx, channels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	out := make(chan Notification, notificationBuffer)
+	go func() {
+		defer cancel()
+		c.runListener(ctx, channels, conn, false, out)
+	}()
+	return out, nil
+}
+
+// untilClosed returns a copy of ctx that is also canceled when the manager
+// is closed.
+func (c *PoolManager) untilClosed(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-c.closeChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// runListener delivers the notifications received on conn to out and
+// reconnects when conn breaks, with a gap marker per channel. A nil conn is
+// connected first, with gap markers only when firstGap is set. out is closed
+// when ctx is done.
+func (c *PoolManager) runListener(ctx context.Context, channels []string, conn *pgx.Conn, firstGap bool, out chan<- Notification) {
+	defer close(out)
+	listened := conn != nil || firstGap
+	for {
+		if conn == nil {
+			var err error
+			conn, err = c.listenConn(ctx, channels)
+			if err != nil {
+				if ctx.Err() != nil || !c.waitListenRetry(ctx, err) {
+					return
+				}
+				continue
+			}
+			if listened {
+				for _, channel := range channels {
+					if !sendNotification(ctx, out, Notification{Channel: channel, Gap: true}) {
+						_ = conn.Close(context.WithoutCancel(ctx))
+						return
+					}
+				}
+			}
+			listened = true
+		}
+
+		err := receiveNotifications(ctx, conn, out)
+		_ = conn.Close(context.WithoutCancel(ctx))
+		conn = nil
+		if ctx.Err() != nil || !c.waitListenRetry(ctx, err) {
+			return
+		}
+	}
+}
+
+// listenConn takes a connection to the primary out of the pool, since it
+// stays subscribed for its lifetime, and issues LISTEN for channels on it.
+func (c *PoolManager) listenConn(ctx context.Context, channels []string) (*pgx.Conn, error) {
+	primary, err := c.Primary()
+	if err != nil {
+		return nil, err
+	}
+	pooled, err := primary.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn := pooled.Hijack()
+
+	for _, channel := range channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			_ = conn.Close(context.WithoutCancel(ctx))
+			return nil, primary.parseErr(err)
+		}
+	}
+	return conn, nil
+}
+
+func receiveNotifications(ctx context.Context, conn *pgx.Conn, out chan<- Notification) error {
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		if !sendNotification(ctx, out, Notification{Channel: n.Channel, Payload: n.Payload, PID: n.PID}) {
+			return ctx.Err()
+		}
+	}
+}
+
+func sendNotification(ctx context.Context, out chan<- Notification, n Notification) bool {
+	select {
+	case out <- n:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// waitListenRetry waits before the next LISTEN attempt after err: until the
+// primary is reconnected when it is unreachable, otherwise for RetryInterval,
+// e.g. when the backend was terminated while the primary still looks
+// healthy. Returns false when ctx is done first.
+func (c *PoolManager) waitListenRetry(ctx context.Context, err error) bool {
+	if errors.Is(err, ErrUnreachable) {
+		return c.waitPrimaryConnected(ctx)
+	}
+	select {
+	case <-time.After(c.config.PrimaryConfig.RetryInterval):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// waitPrimaryConnected blocks until the primary is connected again.
+// Returns false when ctx is done first.
+func (c *PoolManager) waitPrimaryConnected(ctx context.Context) bool {
+	if c.master == nil {
+		return false
+	}
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	changes := c.master.SubscribeStateChange(subCtx)
+
+	for {
+		if c.master.GetState() == HostStateConnected {
+			return true
+		}
+		select {
+		case _, ok := <-changes:
+			if !ok {
+				return false
+			}
+		case <-time.After(c.config.PrimaryConfig.RetryInterval):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
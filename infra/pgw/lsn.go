@@ -0,0 +1,65 @@
+package pgw
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LSN is a position in the write-ahead log. As a consistency token it marks
+// a write on the primary: a standby that replayed up to it sees the write.
+type LSN uint64
+
+// ParseLSN parses the textual form of a pg_lsn, e.g. "16/B374D848".
+func ParseLSN(s string) (LSN, error) {
+	hi, lo, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0, fmt.Errorf("pgw: invalid LSN %q", s)
+	}
+	h, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("pgw: invalid LSN %q: %w", s, err)
+	}
+	l, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("pgw: invalid LSN %q: %w", s, err)
+	}
+	return LSN(h<<32 | l), nil
+}
+
+// String returns the textual form of the LSN, as accepted by ParseLSN and by
+// Postgres.
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint64(l)>>32, uint64(l)&0xFFFFFFFF)
+}
+
+// ConsistencyToken returns the current WAL position of the primary. Take it
+// after a write and pass it on with WithConsistencyToken, possibly to another
+// service as LSN.String, so that reads through StandbyPreferred see the
+// write.
+func (c *PoolManager) ConsistencyToken(ctx context.Context) (LSN, error) {
+	primary, err := c.Primary()
+	if err != nil {
+		return 0, err
+	}
+	var lsn string
+	if err := primary.QueryRow(ctx, "SELECT pg_current_wal_lsn()::text").Scan(&lsn); err != nil {
+		return 0, primary.parseErr(err)
+	}
+	return ParseLSN(lsn)
+}
+
+type ctxKeyConsistencyToken struct{}
+
+// WithConsistencyToken returns a copy of ctx carrying token, see
+// PoolManager.StandbyPreferred.
+func WithConsistencyToken(ctx context.Context, token LSN) context.Context {
+	return context.WithValue(ctx, ctxKeyConsistencyToken{}, token)
+}
+
+// ConsistencyTokenFromContext returns the token set by WithConsistencyToken.
+func ConsistencyTokenFromContext(ctx context.Context) (LSN, bool) {
+	token, ok := ctx.Value(ctxKeyConsistencyToken{}).(LSN)
+	return token, ok
+}
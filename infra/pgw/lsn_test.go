@@ -0,0 +1,76 @@
+package pgw
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseLSN(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    LSN
+		wantErr bool
+	}{
+		{in: "0/0", want: 0},
+		{in: "0/16B3748", want: 0x16B3748},
+		{in: "16/B374D848", want: 0x16_B374D848},
+		{in: "16/b374d848", want: 0x16_B374D848},
+		{in: "FFFFFFFF/FFFFFFFF", want: ^LSN(0)},
+		{in: "", wantErr: true},
+		{in: "16B374D848", wantErr: true},
+		{in: "16/", wantErr: true},
+		{in: "G/0", wantErr: true},
+		{in: "1/100000000", wantErr: true}, // low half overflows 32 bits
+		{in: "0/0/0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLSN(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseLSN(%q) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLSN(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLSN(%q) = %#x, want %#x", tt.in, uint64(got), uint64(tt.want))
+			}
+			if back, _ := ParseLSN(got.String()); back != got {
+				t.Errorf("ParseLSN(%q).String() = %q does not round-trip", tt.in, got.String())
+			}
+		})
+	}
+}
+
+func TestLSNString(t *testing.T) {
+	tests := []struct {
+		lsn  LSN
+		want string
+	}{
+		{lsn: 0, want: "0/0"},
+		{lsn: 0x16B3748, want: "0/16B3748"},
+		{lsn: 0x16_B374D848, want: "16/B374D848"},
+		{lsn: 0x1_00000000, want: "1/0"},
+	}
+	for _, tt := range tests {
+		if got := tt.lsn.String(); got != tt.want {
+			t.Errorf("LSN(%#x).String() = %q, want %q", uint64(tt.lsn), got, tt.want)
+		}
+	}
+}
+
+func TestConsistencyTokenContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := ConsistencyTokenFromContext(ctx); ok {
+		t.Error("empty context should carry no token")
+	}
+
+	ctx = WithConsistencyToken(ctx, 0x16_B374D848)
+	token, ok := ConsistencyTokenFromContext(ctx)
+	if !ok || token != 0x16_B374D848 {
+		t.Errorf("ConsistencyTokenFromContext = %v, %v; want 16/B374D848, true", token, ok)
+	}
+}
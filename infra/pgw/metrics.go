@@ -0,0 +1,217 @@
+package pgw
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const scopeName = "github.com/webitel/webitel-go-kit/infra/pgw"
+
+const (
+	attrRole    = attribute.Key("role")
+	attrHost    = attribute.Key("host")
+	attrFrom    = attribute.Key("from")
+	attrTo      = attribute.Key("to")
+	attrOutcome = attribute.Key("outcome")
+	attrHealth  = attribute.Key("health")
+)
+
+// metrics records the OpenTelemetry metrics of a PoolManager, see
+// WithMeterProvider. Pools carry role ("primary" or "standby") and host
+// attributes; host follows Pool.Host, so it changes after a failover.
+//
+// Instruments:
+//
+//	pgw.pool.connections.acquired  gauge      {role, host}
+//	pgw.pool.connections.idle      gauge      {role, host}
+//	pgw.pool.connections.total     gauge      {role, host}
+//	pgw.pool.acquires              counter    {role, host}
+//	pgw.pool.acquire.wait_time     counter    {role, host}           seconds spent in successful acquires
+//	pgw.pool.acquires.empty        counter    {role, host}           acquires that had to wait for a connection
+//	pgw.health_check.duration      histogram  {role, host, outcome}  seconds; outcome is "ok" or "error"
+//	pgw.health_check.failures      counter    {role, host}
+//	pgw.pool.state.transitions     counter    {role, host, from, to}
+//	pgw.standby.pools              gauge      {health}               health is "healthy" or "unhealthy"
+type metrics struct {
+	healthCheckDur      metric.Float64Histogram
+	healthCheckFailures metric.Int64Counter
+	stateTransitions    metric.Int64Counter
+
+	acquired, idle, total   metric.Int64ObservableGauge
+	acquires, emptyAcquires metric.Int64ObservableCounter
+	acquireWait             metric.Float64ObservableCounter
+	standbys                metric.Int64ObservableGauge
+	registration            metric.Registration
+
+	mu           sync.Mutex
+	pools        map[*Pool]struct{}
+	standbyStats func() (healthy, unhealthy int)
+}
+
+// newMetrics creates the instruments on mp, or on the global MeterProvider
+// when mp is nil.
+func newMetrics(mp metric.MeterProvider) (*metrics, error) {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter(scopeName)
+	m := &metrics{pools: make(map[*Pool]struct{})}
+
+	var err error
+	if m.healthCheckDur, err = meter.Float64Histogram("pgw.health_check.duration",
+		metric.WithDescription("Duration of pool health checks."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if m.healthCheckFailures, err = meter.Int64Counter("pgw.health_check.failures",
+		metric.WithDescription("Number of failed pool health checks."),
+		metric.WithUnit("{check}")); err != nil {
+		return nil, err
+	}
+	if m.stateTransitions, err = meter.Int64Counter("pgw.pool.state.transitions",
+		metric.WithDescription("Number of pool state changes."),
+		metric.WithUnit("{transition}")); err != nil {
+		return nil, err
+	}
+	if m.acquired, err = meter.Int64ObservableGauge("pgw.pool.connections.acquired",
+		metric.WithDescription("Number of connections currently acquired from the pool."),
+		metric.WithUnit("{connection}")); err != nil {
+		return nil, err
+	}
+	if m.idle, err = meter.Int64ObservableGauge("pgw.pool.connections.idle",
+		metric.WithDescription("Number of idle connections in the pool."),
+		metric.WithUnit("{connection}")); err != nil {
+		return nil, err
+	}
+	if m.total, err = meter.Int64ObservableGauge("pgw.pool.connections.total",
+		metric.WithDescription("Number of connections in the pool, including ones being established."),
+		metric.WithUnit("{connection}")); err != nil {
+		return nil, err
+	}
+	if m.acquires, err = meter.Int64ObservableCounter("pgw.pool.acquires",
+		metric.WithDescription("Number of successful connection acquires."),
+		metric.WithUnit("{acquire}")); err != nil {
+		return nil, err
+	}
+	if m.acquireWait, err = meter.Float64ObservableCounter("pgw.pool.acquire.wait_time",
+		metric.WithDescription("Total time spent in successful connection acquires."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if m.emptyAcquires, err = meter.Int64ObservableCounter("pgw.pool.acquires.empty",
+		metric.WithDescription("Number of successful acquires that waited because the pool was empty."),
+		metric.WithUnit("{acquire}")); err != nil {
+		return nil, err
+	}
+	if m.standbys, err = meter.Int64ObservableGauge("pgw.standby.pools",
+		metric.WithDescription("Number of standby pools by health."),
+		metric.WithUnit("{pool}")); err != nil {
+		return nil, err
+	}
+
+	m.registration, err = meter.RegisterCallback(m.observe,
+		m.acquired, m.idle, m.total, m.acquires, m.acquireWait, m.emptyAcquires, m.standbys)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *metrics) observe(_ context.Context, o metric.Observer) error {
+	m.mu.Lock()
+	pools := make([]*Pool, 0, len(m.pools))
+	for p := range m.pools {
+		pools = append(pools, p)
+	}
+	standbyStats := m.standbyStats
+	m.mu.Unlock()
+
+	for _, p := range pools {
+		st := p.Stat()
+		attrs := metric.WithAttributes(p.metricAttrs()...)
+		o.ObserveInt64(m.acquired, int64(st.AcquiredConns()), attrs)
+		o.ObserveInt64(m.idle, int64(st.IdleConns()), attrs)
+		o.ObserveInt64(m.total, int64(st.TotalConns()), attrs)
+		o.ObserveInt64(m.acquires, st.AcquireCount(), attrs)
+		o.ObserveFloat64(m.acquireWait, st.AcquireDuration().Seconds(), attrs)
+		o.ObserveInt64(m.emptyAcquires, st.EmptyAcquireCount(), attrs)
+	}
+	if standbyStats != nil {
+		healthy, unhealthy := standbyStats()
+		o.ObserveInt64(m.standbys, int64(healthy), metric.WithAttributes(attrHealth.String("healthy")))
+		o.ObserveInt64(m.standbys, int64(unhealthy), metric.WithAttributes(attrHealth.String("unhealthy")))
+	}
+	return nil
+}
+
+// The methods below are no-ops on a nil *metrics, so pools built without a
+// PoolManager need no checks.
+
+func (m *metrics) addPool(p *Pool) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.pools[p] = struct{}{}
+	m.mu.Unlock()
+}
+
+func (m *metrics) removePool(p *Pool) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	delete(m.pools, p)
+	m.mu.Unlock()
+}
+
+func (m *metrics) setStandbyStats(fn func() (int, int)) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.standbyStats = fn
+	m.mu.Unlock()
+}
+
+func (m *metrics) healthCheck(ctx context.Context, p *Pool, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	attrs := p.metricAttrs()
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+		m.healthCheckFailures.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+	m.healthCheckDur.Record(ctx, d.Seconds(), metric.WithAttributes(append(attrs, attrOutcome.String(outcome))...))
+}
+
+func (m *metrics) stateTransition(p *Pool, from, to PoolState) {
+	if m == nil {
+		return
+	}
+	m.stateTransitions.Add(context.Background(), 1, metric.WithAttributes(append(p.metricAttrs(),
+		attrFrom.String(string(from)),
+		attrTo.String(string(to)),
+	)...))
+}
+
+func (m *metrics) close() {
+	if m == nil {
+		return
+	}
+	_ = m.registration.Unregister()
+}
+
+func (h *Pool) metricAttrs() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attrRole.String(string(h.config.ExpectedMode)),
+		attrHost.String(h.Host()),
+	}
+}
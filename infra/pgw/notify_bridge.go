@@ -0,0 +1,92 @@
+package pgw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// NotificationHandler receives the notifications of a channel bridged with
+// PoolManager.BridgeNotifications. cache.RowInvalidator implements it to turn
+// row-change notifications into cache invalidations.
+type NotificationHandler interface {
+	// HandleNotification is called for every NOTIFY on the channel.
+	HandleNotification(ctx context.Context, channel, payload string) error
+	// HandleGap is called after every reconnect: notifications sent while
+	// the connection was down are lost, so the handler should resync (e.g.
+	// clear the caches fed by the channel). With WithInitialGap it is also
+	// called once LISTEN is first active.
+	HandleGap(ctx context.Context, channel string) error
+}
+
+// BridgeOption configures PoolManager.BridgeNotifications.
+type BridgeOption func(*bridgeConfig)
+
+type bridgeConfig struct {
+	onError    func(error)
+	initialGap bool
+}
+
+// WithBridgeErrorHandler sets fn to receive the errors returned by the
+// NotificationHandler, wrapped with the channel. By default they are
+// dropped.
+func WithBridgeErrorHandler(fn func(error)) BridgeOption {
+	return func(cfg *bridgeConfig) {
+		cfg.onError = fn
+	}
+}
+
+// WithInitialGap makes the bridge call HandleGap once the first LISTEN is
+// active, for handlers that must also catch up on changes made before the
+// bridge started. By default only reconnects are reported as gaps.
+func WithInitialGap() BridgeOption {
+	return func(cfg *bridgeConfig) {
+		cfg.initialGap = true
+	}
+}
+
+// BridgeNotifications listens on channel over a dedicated connection to the
+// primary and passes every notification to h, until ctx is done or the
+// manager is closed. Unlike Listen, it returns right away and connects in
+// the background.
+//
+// When the connection breaks, the bridge waits for the primary to be
+// reconnected, issues LISTEN again, and calls h.HandleGap. Errors returned by h are
+// not retried; they are passed to the handler set WithBridgeErrorHandler.
+func (c *PoolManager) BridgeNotifications(ctx context.Context, channel string, h NotificationHandler, opts ...BridgeOption) error {
+	if channel == "" {
+		return errors.New("pgw: notification channel must not be empty")
+	}
+	if h == nil {
+		return errors.New("pgw: notification handler must not be nil")
+	}
+
+	var cfg bridgeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx, cancel := c.untilClosed(ctx)
+	notifications := make(chan Notification, notificationBuffer)
+	go func() {
+		defer cancel()
+		c.runListener(ctx, []string{channel}, nil, cfg.initialGap, notifications)
+	}()
+
+	go func() {
+		for n := range notifications {
+			var err error
+			if n.Gap {
+				if err = h.HandleGap(ctx, n.Channel); err != nil {
+					err = fmt.Errorf("pgw: handle gap on %q: %w", n.Channel, err)
+				}
+			} else if err = h.HandleNotification(ctx, n.Channel, n.Payload); err != nil {
+				err = fmt.Errorf("pgw: handle notification on %q: %w", n.Channel, err)
+			}
+			if err != nil && cfg.onError != nil {
+				cfg.onError(err)
+			}
+		}
+	}()
+	return nil
+}
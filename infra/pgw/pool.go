@@ -3,7 +3,10 @@ package pgw
 import (
 	"context"
 	"errors"
+	"net"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -13,14 +16,23 @@ import (
 
 var (
 	ErrMigrationVerify = errors.New("pgw: migration unverified")
+	// ErrRoleMismatch is the health check error of a pool whose server is a
+	// standby although the pool expects a primary, or the other way round.
+	ErrRoleMismatch = errors.New("pgw: server role does not match the pool")
 )
 
 type PoolConfig struct {
 	HealthCheckInterval time.Duration
 	HealthCheckTimeout  time.Duration
 
+	// ExpectedMode, when HostModePrimary or HostModeStandby, makes the health
+	// check verify the server role with pg_is_in_recovery().
+	ExpectedMode HostMode
+
 	ErrorParser       pgErrorParser
 	MigrationVerifier MigrationVerifier
+
+	metrics *metrics
 }
 
 type pgErrorParser interface {
@@ -33,6 +45,13 @@ type Pool struct {
 
 	reconnectMu sync.Mutex
 
+	host           atomic.Value // string, see Host
+	healthChecking atomic.Bool
+
+	// Sampled by the health check of standby pools.
+	replayLSN      atomic.Uint64
+	replicationLag atomic.Int64
+
 	closeOnce sync.Once
 	closeChan chan struct{}
 
@@ -65,6 +84,10 @@ func newPool(pool *pgxpool.Pool, cfg PoolConfig) (*Pool, error) {
 		closeChan: make(chan struct{}),
 		config:    &cfg,
 	}
+	connConfig := pool.Config().ConnConfig
+	node.host.Store(net.JoinHostPort(connConfig.Host, strconv.Itoa(int(connConfig.Port))))
+	node.state.onChange = func(from, to PoolState) { cfg.metrics.stateTransition(node, from, to) }
+	cfg.metrics.addPool(node)
 
 	return node, nil
 }
@@ -134,7 +157,9 @@ func (h *Pool) ConnectWithRetry(maxAttempts int, strategy RetryStrategy, baseVal
 }
 
 func (h *Pool) validateHealth(ctx context.Context) error {
-	err := h.pool.Ping(ctx)
+	start := time.Now()
+	err := h.checkServer(ctx)
+	h.config.metrics.healthCheck(ctx, h, time.Since(start), err)
 	if err != nil {
 		if h.state.Get() != HostStateError {
 			h.state.Set(HostStateError)
@@ -147,6 +172,88 @@ func (h *Pool) validateHealth(ctx context.Context) error {
 	return nil
 }
 
+// checkServer pings a connection of the pool and, with an ExpectedMode,
+// verifies the role of its server. On a role mismatch the connections are
+// reset, so that reconnecting picks a matching host of a multi-host DSN.
+func (h *Pool) checkServer(ctx context.Context) error {
+	conn, err := h.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	h.host.Store(conn.Conn().PgConn().Conn().RemoteAddr().String())
+
+	switch h.config.ExpectedMode {
+	case HostModePrimary:
+		var inRecovery bool
+		if err := conn.QueryRow(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery); err != nil {
+			return err
+		}
+		if inRecovery {
+			h.pool.Reset()
+			return ErrRoleMismatch
+		}
+		return nil
+	case HostModeStandby:
+		return h.sampleReplication(ctx, conn)
+	default:
+		return conn.Ping(ctx)
+	}
+}
+
+// standbyStatusQuery returns whether the server is in recovery, the WAL
+// position it replayed and its replication lag in seconds. The lag is zero
+// when everything received was replayed, so an idle primary does not make
+// its standbys look behind.
+const standbyStatusQuery = `SELECT pg_is_in_recovery(),
+	COALESCE(pg_last_wal_replay_lsn()::text, ''),
+	COALESCE(CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END, 0)::float8`
+
+func (h *Pool) sampleReplication(ctx context.Context, conn *pgxpool.Conn) error {
+	var (
+		inRecovery bool
+		replayLSN  string
+		lagSeconds float64
+	)
+	if err := conn.QueryRow(ctx, standbyStatusQuery).Scan(&inRecovery, &replayLSN, &lagSeconds); err != nil {
+		return err
+	}
+	if !inRecovery {
+		h.pool.Reset()
+		return ErrRoleMismatch
+	}
+	if replayLSN != "" {
+		lsn, err := ParseLSN(replayLSN)
+		if err != nil {
+			return err
+		}
+		h.replayLSN.Store(uint64(lsn))
+	}
+	h.replicationLag.Store(int64(lagSeconds * float64(time.Second)))
+	return nil
+}
+
+// ReplayLSN returns the WAL position a standby pool had replayed at its last
+// health check.
+func (h *Pool) ReplayLSN() LSN {
+	return LSN(h.replayLSN.Load())
+}
+
+// ReplicationLag returns the replication lag of a standby pool at its last
+// health check.
+func (h *Pool) ReplicationLag() time.Duration {
+	return time.Duration(h.replicationLag.Load())
+}
+
+// Host returns the address of the server the pool reached at the last health
+// check, or the configured host before the first one. With a multi-host DSN
+// it tells which candidate is in use.
+func (h *Pool) Host() string {
+	host, _ := h.host.Load().(string)
+	return host
+}
+
 func (h *Pool) Stat() *pgxpool.Stat {
 	return h.pool.Stat()
 }
@@ -156,6 +263,12 @@ func (h *Pool) SubscribeStateChange(ctx context.Context) <-chan PoolState {
 }
 
 func (h *Pool) healthCheck() {
+	// Reconnects start a new health check; keep a single one per pool.
+	if !h.healthChecking.CompareAndSwap(false, true) {
+		return
+	}
+	defer h.healthChecking.Store(false)
+
 	ticker := time.NewTicker(h.config.HealthCheckInterval)
 	defer ticker.Stop()
 
@@ -182,6 +295,7 @@ func (h *Pool) Close() {
 
 		h.state.Close()
 
+		h.config.metrics.removePool(h)
 	})
 }
 
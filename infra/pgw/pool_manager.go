@@ -27,6 +27,10 @@ type PoolManager struct {
 
 	errorsManager *errorsManager
 
+	topology *topology
+
+	metrics *metrics
+
 	closeChan chan struct{}
 }
 
@@ -52,9 +56,12 @@ func (c *PoolManager) Standby() (*Pool, error) {
 }
 
 // StandbyPreferred returns the standby pool if it is connected, otherwise it returns the primary pool.
+// When ctx carries a consistency token (see WithConsistencyToken), only a standby that has replayed
+// past the token is picked, so the caller reads its own writes.
 // It returns an error if the primary pool is not connected.
-func (c *PoolManager) StandbyPreferred() (*Pool, error) {
-	host := c.standbyManager.Pick()
+func (c *PoolManager) StandbyPreferred(ctx context.Context) (*Pool, error) {
+	token, _ := ConsistencyTokenFromContext(ctx)
+	host := c.standbyManager.PickReplayed(token)
 	if host == nil {
 		return c.Primary()
 	}
@@ -62,6 +69,19 @@ func (c *PoolManager) StandbyPreferred() (*Pool, error) {
 	return host, nil
 }
 
+// PrimaryHost returns the address of the server currently used as the
+// primary, see Pool.Host.
+func (c *PoolManager) PrimaryHost() string {
+	return c.topology.Primary()
+}
+
+// SubscribeTopologyChange returns a channel that receives a TopologyChange
+// whenever another host became the primary. The channel is closed when ctx
+// is done or the manager is closed; slow receivers miss changes.
+func (c *PoolManager) SubscribeTopologyChange(ctx context.Context) <-chan TopologyChange {
+	return c.topology.SubscribeChange(ctx)
+}
+
 // RegisterUniqueViolation registers a unique violation error processor for the given constraint name.
 func (e *PoolManager) RegisterUniqueViolation(constraintName string, processor ErrorProcessor) error {
 	return e.errorsManager.RegisterUniqueViolation(constraintName, processor)
@@ -89,12 +109,15 @@ func (c *PoolManager) Close() {
 		c.master.Close()
 	}
 	c.standbyManager.Close()
+	c.topology.Close()
+	c.metrics.close()
 }
 
 func NewPoolManager(ctx context.Context, options ...ConfigOption) (*PoolManager, error) {
 	cfg := &Config{
 		PrimaryConfig: DefaultPrimaryPoolConfig,
 		StandbyConfig: DefaultStandbyPoolConfig,
+		TxConfig:      DefaultTxConfig,
 	}
 
 	for _, opt := range options {
@@ -102,23 +125,34 @@ func NewPoolManager(ctx context.Context, options ...ConfigOption) (*PoolManager,
 	}
 	cfg.PrimaryConfig = mergePrimaryPoolConfigWithDefault(cfg.PrimaryConfig)
 	cfg.StandbyConfig = mergeStandbyPoolConfigWithDefault(cfg.StandbyConfig)
+	cfg.TxConfig = mergeTxConfigWithDefault(cfg.TxConfig)
+
+	m, err := newMetrics(cfg.MeterProvider)
+	if err != nil {
+		return nil, err
+	}
 
 	conn := &PoolManager{
 		config:        cfg,
 		closeChan:     make(chan struct{}),
 		errorsManager: newErrorsManager(),
+		topology:      newTopology(),
+		metrics:       m,
 	}
 
-	err := conn.initPrimary(ctx, cfg)
+	err = conn.initPrimary(ctx, cfg)
 	if err != nil {
+		m.close()
 		return nil, err
 	}
 
 	err = conn.initStandby(ctx, cfg)
 	if err != nil {
 		conn.master.Close()
+		m.close()
 		return nil, err
 	}
+	m.setStandbyStats(conn.standbyManager.Stats)
 
 	return conn, nil
 }
@@ -181,9 +215,26 @@ func mergeStandbyPoolConfigWithDefault(config StandbyConfig) StandbyConfig {
 	if config.MinConns > 0 {
 		mergedPoolConfig.MinConns = config.MinConns
 	}
+	if config.MaxLag > 0 {
+		mergedPoolConfig.MaxLag = config.MaxLag
+	}
 	return mergedPoolConfig
 }
 
+func mergeTxConfigWithDefault(config TxConfig) TxConfig {
+	mergedConfig := DefaultTxConfig
+	if config.MaxAttempts > 0 {
+		mergedConfig.MaxAttempts = config.MaxAttempts
+	}
+	if config.Backoff > 0 {
+		mergedConfig.Backoff = config.Backoff
+	}
+	if config.MaxBackoff > 0 {
+		mergedConfig.MaxBackoff = config.MaxBackoff
+	}
+	return mergedConfig
+}
+
 func (c *PoolManager) initPrimary(ctx context.Context, config *Config) error {
 	if config == nil {
 		return errors.New("primary config is required to run pgw")
@@ -199,8 +250,10 @@ func (c *PoolManager) initPrimary(ctx context.Context, config *Config) error {
 	defaultPool, err := newPool(pool, PoolConfig{
 		HealthCheckInterval: primaryConfig.HealthCheckInterval,
 		HealthCheckTimeout:  primaryConfig.HealthCheckTimeout,
+		ExpectedMode:        HostModePrimary,
 		ErrorParser:         c.errorsManager,
 		MigrationVerifier:   config.MigrationVerifier,
+		metrics:             c.metrics,
 	})
 	if err != nil {
 		pool.Close()
@@ -218,6 +271,7 @@ func (c *PoolManager) initPrimary(ctx context.Context, config *Config) error {
 	}
 
 	c.master = defaultPool
+	c.topology.SetPrimary(defaultPool.Host())
 	go c.startMasterMonitor()
 
 	return nil
@@ -285,11 +339,15 @@ func (c *PoolManager) reconnectMasterLoop() {
 		case <-c.closeChan:
 			return
 		case <-time.After(masterCfg.RetryInterval):
+			// Drop the connections to the failed host, so that new ones go
+			// to whichever candidate of the DSN is writable now.
+			c.master.pool.Reset()
 			err := c.master.ConnectWithRetry(
 				masterCfg.RetryAttempts,
 				masterCfg.RetryStrategy,
 				masterCfg.RetryStrategyBaseValue)
 			if err == nil {
+				c.updatePrimaryHost()
 				go c.startMasterMonitor()
 				return
 			}
@@ -297,6 +355,18 @@ func (c *PoolManager) reconnectMasterLoop() {
 	}
 }
 
+// updatePrimaryHost records the host the primary pool reconnected to. When
+// it is another host than before, the standbys are re-balanced, since the
+// new primary was likely one of them, and subscribers are notified.
+func (c *PoolManager) updatePrimaryHost() {
+	change, changed := c.topology.SetPrimary(c.master.Host())
+	if !changed {
+		return
+	}
+	c.standbyManager.Rebalance()
+	c.topology.NotifyChange(change)
+}
+
 func (c *PoolManager) initStandby(ctx context.Context, config *Config) error {
 	var (
 		replicaConfig = config.StandbyConfig
@@ -309,8 +379,10 @@ func (c *PoolManager) initStandby(ctx context.Context, config *Config) error {
 		RetryStrategyBaseValue:        replicaConfig.RetryStrategyBaseValue,
 		HostHealthCheckInterval:       replicaConfig.HealthCheckInterval,
 		HostHealthCheckTimeout:        replicaConfig.HealthCheckTimeout,
+		MaxLag:                        replicaConfig.MaxLag,
 		ErrorParser:                   c.errorsManager,
 		MigrationVerifier:             config.MigrationVerifier,
+		Metrics:                       c.metrics,
 	})
 	if err != nil {
 		return err
@@ -33,6 +33,8 @@ type poolState struct {
 
 	notifyChangeChannels []chan PoolState
 	closeChan            chan struct{}
+
+	onChange func(from, to PoolState) // called under stateMu
 }
 
 func (s *poolState) Set(state PoolState) {
@@ -42,7 +44,11 @@ func (s *poolState) Set(state PoolState) {
 		return
 	}
 
+	from := s.state
 	s.state = state
+	if s.onChange != nil {
+		s.onChange(from, state)
+	}
 	s.notifyChange()
 }
 
@@ -20,8 +20,12 @@ type standbyManagerConfig struct {
 	HostHealthCheckInterval time.Duration
 	HostHealthCheckTimeout  time.Duration
 
+	MaxLag time.Duration
+
 	ErrorParser       pgErrorParser
 	MigrationVerifier MigrationVerifier
+
+	Metrics *metrics
 }
 
 type standbyManager struct {
@@ -31,7 +35,8 @@ type standbyManager struct {
 
 	unhealthyStore *safemap.SafeMap[string, *Pool]
 
-	closeChan chan struct{}
+	recheckChan chan struct{}
+	closeChan   chan struct{}
 }
 
 func (cfg *standbyManagerConfig) normalizeConfig() {
@@ -67,7 +72,8 @@ func newStandbyManager(cfg standbyManagerConfig) (*standbyManager, error) {
 		store:          safemap.New[string, *Pool](nil),
 		unhealthyStore: safemap.New[string, *Pool](nil),
 
-		closeChan: make(chan struct{}),
+		recheckChan: make(chan struct{}, 1),
+		closeChan:   make(chan struct{}),
 	}
 
 	go manager.monitorUnhealthy()
@@ -80,15 +86,38 @@ func (rm *standbyManager) Stats() (int, int) {
 }
 
 func (rm *standbyManager) Pick() *Pool {
-	return rm.config.PickStrategy(rm.store)
+	return rm.PickReplayed(0)
+}
+
+// PickReplayed picks among the standbys within MaxLag that have replayed at
+// least up to minLSN.
+func (rm *standbyManager) PickReplayed(minLSN LSN) *Pool {
+	if rm.config.MaxLag <= 0 && minLSN == 0 {
+		return rm.config.PickStrategy(rm.store)
+	}
+
+	eligible := safemap.New[string, *Pool](nil)
+	rm.store.Range(func(key string, host *Pool) error {
+		if rm.config.MaxLag > 0 && host.ReplicationLag() > rm.config.MaxLag {
+			return nil
+		}
+		if host.ReplayLSN() < minLSN {
+			return nil
+		}
+		eligible.Set(key, host)
+		return nil
+	})
+	return rm.config.PickStrategy(eligible)
 }
 
 func (rm *standbyManager) AddStandby(ctx context.Context, pool *pgxpool.Pool) error {
 	host, err := newPool(pool, PoolConfig{
 		HealthCheckInterval: rm.config.HostHealthCheckInterval,
 		HealthCheckTimeout:  rm.config.HostHealthCheckTimeout,
+		ExpectedMode:        HostModeStandby,
 		ErrorParser:         rm.config.ErrorParser,
 		MigrationVerifier:   rm.config.MigrationVerifier,
+		metrics:             rm.config.Metrics,
 	})
 	if err != nil {
 		return err
@@ -155,6 +184,26 @@ func (rm *standbyManager) moveToUnhealthy(key string) {
 	rm.unhealthyStore.Set(key, host)
 }
 
+// Rebalance re-checks the standby set after the primary moved to another
+// host: healthy standbys are health-checked right away, which moves a
+// promoted one out, and unhealthy ones are retried without waiting for
+// UnhealthyStandbyRetryInterval, which brings a demoted former primary in.
+func (rm *standbyManager) Rebalance() {
+	rm.store.Range(func(key string, host *Pool) error {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), rm.config.HostHealthCheckTimeout)
+			defer cancel()
+			_ = host.validateHealth(ctx)
+		}()
+		return nil
+	})
+
+	select {
+	case rm.recheckChan <- struct{}{}:
+	default:
+	}
+}
+
 func (rm *standbyManager) monitorUnhealthy() {
 	ticker := time.NewTicker(rm.config.UnhealthyStandbyRetryInterval)
 	defer ticker.Stop()
@@ -162,50 +211,9 @@ func (rm *standbyManager) monitorUnhealthy() {
 	for {
 		select {
 		case <-ticker.C:
-			if rm.unhealthyStore.Len() == 0 {
-				continue
-			}
-
-			var (
-				wg                = sync.WaitGroup{}
-				needMoveToHealthy = safemap.New[string, *Pool](nil)
-			)
-
-			rm.unhealthyStore.Range(func(key string, replica *Pool) error {
-				wg.Add(1)
-				go func(host *Pool) {
-					defer wg.Done()
-					if host.GetState() == HostStateClosed {
-						rm.unhealthyStore.Remove(key)
-						return
-					}
-					err := host.ConnectWithRetry(
-						rm.config.RetriesBeforeUnhealthy,
-						rm.config.RetryStrategy,
-						rm.config.RetryStrategyBaseValue)
-
-					if err != nil {
-						// TODO: log error
-						return
-					}
-					needMoveToHealthy.Set(rm.buildMapKey(host), host)
-				}(replica)
-
-				return nil
-			})
-			wg.Wait()
-
-			needMoveToHealthy.Range(func(key string, host *Pool) error {
-				go func(k string, h *Pool) {
-					go rm.monitorStateChange(k, host)
-
-					rm.unhealthyStore.Remove(k)
-					rm.store.Set(k, h)
-				}(key, host)
-
-				return nil
-			})
-
+			rm.retryUnhealthy()
+		case <-rm.recheckChan:
+			rm.retryUnhealthy()
 		case <-rm.closeChan:
 			return
 		}
@@ -213,6 +221,52 @@ func (rm *standbyManager) monitorUnhealthy() {
 	}
 }
 
+func (rm *standbyManager) retryUnhealthy() {
+	if rm.unhealthyStore.Len() == 0 {
+		return
+	}
+
+	var (
+		wg                = sync.WaitGroup{}
+		needMoveToHealthy = safemap.New[string, *Pool](nil)
+	)
+
+	rm.unhealthyStore.Range(func(key string, replica *Pool) error {
+		wg.Add(1)
+		go func(host *Pool) {
+			defer wg.Done()
+			if host.GetState() == HostStateClosed {
+				rm.unhealthyStore.Remove(key)
+				return
+			}
+			err := host.ConnectWithRetry(
+				rm.config.RetriesBeforeUnhealthy,
+				rm.config.RetryStrategy,
+				rm.config.RetryStrategyBaseValue)
+
+			if err != nil {
+				// TODO: log error
+				return
+			}
+			needMoveToHealthy.Set(rm.buildMapKey(host), host)
+		}(replica)
+
+		return nil
+	})
+	wg.Wait()
+
+	needMoveToHealthy.Range(func(key string, host *Pool) error {
+		go func(k string, h *Pool) {
+			go rm.monitorStateChange(k, host)
+
+			rm.unhealthyStore.Remove(k)
+			rm.store.Set(k, h)
+		}(key, host)
+
+		return nil
+	})
+}
+
 func (rm *standbyManager) buildMapKey(host *Pool) string {
 	return rm.buildMapKeyFromPool(host.pool)
 }
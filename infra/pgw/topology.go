@@ -0,0 +1,96 @@
+package pgw
+
+import (
+	"context"
+	"sync"
+)
+
+// TopologyChange is sent to PoolManager.SubscribeTopologyChange subscribers
+// when another host became the primary, e.g. after a failover.
+type TopologyChange struct {
+	// PreviousPrimary and Primary are server addresses, see Pool.Host.
+	PreviousPrimary string
+	Primary         string
+}
+
+type topology struct {
+	primary    string
+	primaryMu  sync.RWMutex
+	channelsMu sync.RWMutex
+
+	notifyChangeChannels []chan TopologyChange
+	closeChan            chan struct{}
+}
+
+func newTopology() *topology {
+	return &topology{closeChan: make(chan struct{})}
+}
+
+// SetPrimary records host as the primary and reports the change, if any.
+// The first recorded host is not a change.
+func (t *topology) SetPrimary(host string) (TopologyChange, bool) {
+	t.primaryMu.Lock()
+	defer t.primaryMu.Unlock()
+	if host == "" || host == t.primary {
+		return TopologyChange{}, false
+	}
+	change := TopologyChange{PreviousPrimary: t.primary, Primary: host}
+	t.primary = host
+	return change, change.PreviousPrimary != ""
+}
+
+func (t *topology) Primary() string {
+	t.primaryMu.RLock()
+	defer t.primaryMu.RUnlock()
+	return t.primary
+}
+
+func (t *topology) SubscribeChange(ctx context.Context) <-chan TopologyChange {
+	ch := make(chan TopologyChange, 4)
+
+	t.channelsMu.Lock()
+	t.notifyChangeChannels = append(t.notifyChangeChannels, ch)
+	t.channelsMu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			t.channelsMu.Lock()
+			defer t.channelsMu.Unlock()
+			for i, c := range t.notifyChangeChannels {
+				if c == ch {
+					t.notifyChangeChannels = append(t.notifyChangeChannels[:i], t.notifyChangeChannels[i+1:]...)
+					close(ch)
+					return
+				}
+			}
+		case <-t.closeChan:
+			return
+		}
+	}()
+
+	return ch
+}
+
+func (t *topology) NotifyChange(change TopologyChange) {
+	t.channelsMu.RLock()
+	defer t.channelsMu.RUnlock()
+	for _, ch := range t.notifyChangeChannels {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
+
+func (t *topology) Close() {
+	t.channelsMu.Lock()
+	defer t.channelsMu.Unlock()
+	close(t.closeChan)
+
+	for _, ch := range t.notifyChangeChannels {
+		close(ch)
+	}
+
+	t.notifyChangeChannels = nil
+}
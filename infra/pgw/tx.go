@@ -0,0 +1,121 @@
+package pgw
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TxFunc is the body of a transaction run by PoolManager.InTx. ctx carries tx,
+// so a nested InTx call joins it with a savepoint.
+type TxFunc func(ctx context.Context, tx pgx.Tx) error
+
+type ctxKeyTx struct{}
+
+// TxFromContext returns the transaction of an enclosing InTx call, so
+// repositories can run on it when there is one.
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(ctxKeyTx{}).(pgx.Tx)
+	return tx, ok
+}
+
+// InTx runs fn in a transaction on the primary, started with opts. The
+// transaction is committed when fn returns nil and rolled back otherwise,
+// also when fn panics.
+//
+// A serialization failure (40001) or deadlock (40P01) from fn or from the
+// commit runs the whole transaction again, with backoff, up to
+// TxConfig.MaxAttempts times, so fn must be safe to repeat.
+//
+// When ctx already carries a transaction of an enclosing InTx, fn runs in a
+// savepoint of it instead and opts is ignored: an error rolls back to the
+// savepoint and is returned to the enclosing fn unchanged; retries are left
+// to the outermost call.
+//
+// Errors of the outermost call are passed through the registered constraint
+// processors; a nested call leaves that to it, so they run exactly once.
+func (c *PoolManager) InTx(ctx context.Context, opts pgx.TxOptions, fn TxFunc) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return runSavepoint(ctx, tx, fn)
+	}
+
+	primary, err := c.Primary()
+	if err != nil {
+		return err
+	}
+
+	cfg := c.config.TxConfig
+	for attempt := 1; ; attempt++ {
+		err = runTx(ctx, primary, opts, fn)
+		if err == nil || attempt >= cfg.MaxAttempts || !isRetryableTxError(err) {
+			return c.errorsManager.ParsePgError(err)
+		}
+		select {
+		case <-time.After(cfg.backoff(attempt)):
+		case <-ctx.Done():
+			return c.errorsManager.ParsePgError(err)
+		}
+	}
+}
+
+func runTx(ctx context.Context, pool *Pool, opts pgx.TxOptions, fn TxFunc) error {
+	tx, err := pool.pool.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	// A no-op after Commit; rolls back on error and panic.
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	if err := fn(context.WithValue(ctx, ctxKeyTx{}, tx), tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// runSavepoint runs fn in a pseudo nested transaction of tx. Its errors
+// reach the enclosing InTx unchanged: still a *pgconn.PgError, so retryable
+// ones run the whole transaction again and the constraint processors see
+// the original error.
+func runSavepoint(ctx context.Context, tx pgx.Tx, fn TxFunc) error {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sp.Rollback(context.WithoutCancel(ctx)) }()
+
+	if err := fn(context.WithValue(ctx, ctxKeyTx{}, sp), sp); err != nil {
+		return err
+	}
+	return sp.Commit(ctx)
+}
+
+// isRetryableTxError reports whether err aborted the transaction because of
+// concurrent transactions, so running it again may succeed.
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.Code {
+	case "40001", "40P01":
+		// serialization failure, deadlock detected
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before retry number attempt: Backoff doubled per
+// earlier retry, capped at MaxBackoff, with jitter down to half of it so
+// conflicting transactions do not collide again.
+func (cfg TxConfig) backoff(attempt int) time.Duration {
+	d := cfg.Backoff
+	for i := 1; i < attempt && d < cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, cfg.MaxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
@@ -0,0 +1,56 @@
+package pgw
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsRetryableTxError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "plain", err: errors.New("boom"), want: false},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: "40P01"}, want: true},
+		{name: "wrapped deadlock", err: fmt.Errorf("commit: %w", &pgconn.PgError{Code: "40P01"}), want: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "other rollback class", err: &pgconn.PgError{Code: "40002"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableTxError(tt.err); got != tt.want {
+				t.Errorf("isRetryableTxError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTxConfigBackoff(t *testing.T) {
+	cfg := TxConfig{Backoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	tests := []struct {
+		attempt int
+		base    time.Duration // before jitter
+	}{
+		{attempt: 1, base: 10 * time.Millisecond},
+		{attempt: 2, base: 20 * time.Millisecond},
+		{attempt: 3, base: 40 * time.Millisecond},
+		{attempt: 4, base: 50 * time.Millisecond},
+		{attempt: 30, base: 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.attempt), func(t *testing.T) {
+			for range 100 {
+				if d := cfg.backoff(tt.attempt); d < tt.base/2 || d > tt.base {
+					t.Fatalf("backoff(%d) = %v, want within [%v, %v]", tt.attempt, d, tt.base/2, tt.base)
+				}
+			}
+		})
+	}
+}
@@ -62,7 +62,8 @@ func (b *Connection) connect() error {
 	defer b.mu.Unlock()
 
 	conn, err := amqp091.DialConfig(b.cfg.URL, amqp091.Config{
-		Dial: amqp091.DefaultDial(b.cfg.ConnectTimeout),
+		Dial:            amqp091.DefaultDial(b.cfg.ConnectTimeout),
+		TLSClientConfig: b.cfg.TLSConfig,
 	})
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrConnectionFailed, err)
@@ -1,6 +1,7 @@
 package rabbitmq
 
 import (
+	"crypto/tls"
 	"errors"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"time"
@@ -19,6 +20,8 @@ const (
 type Config struct {
 	URL            string
 	ConnectTimeout time.Duration
+	// TLSConfig is used for amqps:// URLs; nil uses the system roots.
+	TLSConfig *tls.Config
 }
 
 // NewConfig creates a new Config with URL and connect timeout validation.
@@ -47,6 +50,13 @@ func WithConnectTimeout(timeout time.Duration) ConfigOption {
 	}
 }
 
+// WithTLSConfig sets the TLS configuration of amqps:// connections.
+func WithTLSConfig(tlsConfig *tls.Config) ConfigOption {
+	return func(config *Config) {
+		config.TLSConfig = tlsConfig
+	}
+}
+
 type ExchangeConfig struct {
 	Name       string
 	Type       MQExchangeType
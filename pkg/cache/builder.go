@@ -3,6 +3,9 @@ package cache
 import (
 	"errors"
 	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Builder constructs a Cache[K, V] with optional L1 (Ristretto) and L2 (Redis)
@@ -12,10 +15,12 @@ import (
 // Layer wiring order (inside → out):
 //
 //	L1 raw → instrument(l1) → \
-//	                            multiLevel → loadingCache
-//	L2 raw → circuitBreaker → instrument(l2) → /
+//	                                     multiLevel → loadingCache
+//	L2 raw → trace → circuitBreaker → instrument(l2) → /
+//
+// The loader itself is wrapped with a "cache.Load" span and Stats.LoadDuration.
 //
-// Usage — L1 + L2 with singleflight loader and OTel stats:
+// Usage — L1 + L2 with singleflight loader, OTel stats and tracing:
 //
 //	c, err := cache.New[string, *pb.Contact]().
 //	    Name("contact").
@@ -27,7 +32,9 @@ import (
 //	        Codec:  cache.Proto(func() *pb.Contact { return &pb.Contact{} }),
 //	    }).
 //	    WithCircuitBreaker(cache.CircuitBreakerConfig{Threshold: 5, Timeout: 10 * time.Second}).
-//	    WithStats(myOtelStats).
+//	    WithInvalidation(cache.InvalidationConfig{}).
+//	    WithStats(otelStats). // from cache.OTelStats(meterProvider)
+//	    WithTracing(tracerProvider).
 //	    WithLoader(func(ctx context.Context, id string) (*pb.Contact, error) {
 //	        return db.GetContact(ctx, id)
 //	    }, cache.LoadingConfig{
@@ -39,10 +46,18 @@ type Builder[K comparable, V any] struct {
 	name    string
 	l1Cfg   *RistrettoConfig
 	l2Cfg   *RedisConfig[V]
+	codec   Codec[V]
 	keyFn   KeyFunc[K]
 	cbCfg   *CircuitBreakerConfig
+	invCfg  *InvalidationConfig
+	hotCfg  *HotKeyConfig
+	ttlFn   TTLFunc[K, V]
+	jitter  float64
+	costFn  CostFunc[V]
 	stats   Stats
+	tp      trace.TracerProvider
 	loader  LoadFunc[K, V]
+	batchFn BatchLoadFunc[K, V]
 	loadCfg LoadingConfig
 }
 
@@ -68,7 +83,7 @@ func (b *Builder[K, V]) KeyFunc(fn KeyFunc[K]) *Builder[K, V] {
 }
 
 // L1 configures the Ristretto in-memory layer.
-// Keep L1 TTL shorter than L2 TTL to bound staleness without pub/sub invalidation.
+// Keep L1 TTL shorter than L2 TTL to bound staleness, or enable WithInvalidation.
 func (b *Builder[K, V]) L1(cfg RistrettoConfig) *Builder[K, V] {
 	b.l1Cfg = &cfg
 	return b
@@ -80,6 +95,37 @@ func (b *Builder[K, V]) L2(cfg RedisConfig[V]) *Builder[K, V] {
 	return b
 }
 
+// WithTTLFunc derives the TTL of every entry from its key and value.
+// It applies to Set, SetMany, SetTagged and loader writes; SetTTL keeps the
+// explicit TTL. In a multilevel cache, the L1 TTL is capped at
+// RistrettoConfig.TTL so L1 staleness stays bounded.
+func (b *Builder[K, V]) WithTTLFunc(fn TTLFunc[K, V]) *Builder[K, V] {
+	b.ttlFn = fn
+	return b
+}
+
+// WithTTLJitter randomizes every TTL by ±fraction (see Jitter) in all layers,
+// so entries written together do not expire together. fraction must be in
+// [0, 1); 0.1 spreads a 1h TTL over 54m–66m. SetTTL is not jittered.
+func (b *Builder[K, V]) WithTTLJitter(fraction float64) *Builder[K, V] {
+	b.jitter = fraction
+	return b
+}
+
+// WithCostFunc sets the Ristretto cost of each L1 entry, replacing
+// RistrettoConfig.DefaultCost. Use CodecCost for a size-based cost.
+func (b *Builder[K, V]) WithCostFunc(fn CostFunc[V]) *Builder[K, V] {
+	b.costFn = fn
+	return b
+}
+
+// WithSnapshotCodec sets the codec Snapshot and Restore use to serialize L1
+// values. Defaults to RedisConfig.Codec; required for L1-only caches.
+func (b *Builder[K, V]) WithSnapshotCodec(codec Codec[V]) *Builder[K, V] {
+	b.codec = codec
+	return b
+}
+
 // WithCircuitBreaker applies a circuit breaker to the L2 layer.
 // When L2 errors exceed Threshold, the circuit opens and L2 is bypassed
 // for Timeout duration — the cache degrades to L1-only without crashing.
@@ -89,6 +135,34 @@ func (b *Builder[K, V]) WithCircuitBreaker(cfg CircuitBreakerConfig) *Builder[K,
 	return b
 }
 
+// WithTracing opens OpenTelemetry spans around every L2 call and every
+// loader call, so cache misses show up in traces. Spans are only started
+// under a recording parent span. A nil tp uses the global TracerProvider.
+func (b *Builder[K, V]) WithTracing(tp trace.TracerProvider) *Builder[K, V] {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	b.tp = tp
+	return b
+}
+
+// WithInvalidation broadcasts Set/SetTTL/Delete over Redis pub/sub so that
+// every process sharing this cache Name evicts the key from its L1.
+// Requires both L1 and L2; no-op otherwise.
+func (b *Builder[K, V]) WithInvalidation(cfg InvalidationConfig) *Builder[K, V] {
+	b.invCfg = &cfg
+	return b
+}
+
+// WithHotKeys enables hot-key detection, and optionally L1 promotion of hot
+// keys, see HotKeyConfig. When the Stats passed to WithStats implements
+// HotKeyStats, hot keys are reported to it.
+// Requires both L1 and L2; no-op otherwise.
+func (b *Builder[K, V]) WithHotKeys(cfg HotKeyConfig) *Builder[K, V] {
+	b.hotCfg = &cfg
+	return b
+}
+
 // WithStats wires an observability hook. Hit/Miss/Error are called per layer.
 func (b *Builder[K, V]) WithStats(s Stats) *Builder[K, V] {
 	b.stats = s
@@ -107,32 +181,77 @@ func (b *Builder[K, V]) WithLoader(fn LoadFunc[K, V], cfg ...LoadingConfig) *Bui
 	return b
 }
 
+// WithBatchLoader attaches a batching loading function invoked on cache miss.
+// Misses from Get and GetMany are collected for LoadingConfig.BatchWait (or
+// until MaxBatchSize keys are queued) and loaded with a single fn call, like
+// a dataloader. Keys missing from the returned map are treated as ErrNotFound.
+// Takes precedence over WithLoader when both are set.
+func (b *Builder[K, V]) WithBatchLoader(fn BatchLoadFunc[K, V], cfg ...LoadingConfig) *Builder[K, V] {
+	b.batchFn = fn
+	if len(cfg) > 0 {
+		b.loadCfg = cfg[0]
+	}
+	return b
+}
+
 // Build assembles and returns the configured cache.
-// Returns an error when neither L1 nor L2 is configured, or L1 init fails.
+// Returns an error when neither L1 nor L2 is configured, the TTL jitter is out
+// of range, L2 runs on a Cluster or Ring client without a Prefix, or L1 init
+// fails.
 func (b *Builder[K, V]) Build() (Cache[K, V], error) {
 	if b.l1Cfg == nil && b.l2Cfg == nil {
 		return nil, errors.New("cache: at least one layer (L1 or L2) must be configured")
 	}
+	if b.jitter < 0 || b.jitter >= 1 {
+		return nil, fmt.Errorf("cache: TTL jitter %v must be in [0, 1)", b.jitter)
+	}
+	if b.l2Cfg != nil && b.l2Cfg.Prefix == "" && redisShardsKeys(b.l2Cfg.Client) {
+		// Without a hash tag, MGET, multi-key DEL and the tag pipelines span
+		// slots and fail with CROSSSLOT.
+		return nil, errors.New("cache: L2 Prefix is required on a Redis Cluster or Ring client")
+	}
+	ttlPolicy := newTTLPolicy(b.ttlFn, b.jitter)
 
 	name := b.name
+	var tracer trace.Tracer
+	if b.tp != nil {
+		tracer = b.tp.Tracer(scopeName)
+	}
+
 	var (
-		l1  Cache[K, V]
-		l2  Cache[K, V]
-		err error
+		l1    Cache[K, V]
+		l1raw *ristrettoCache[K, V]
+		l2    Cache[K, V]
+		err   error
 	)
 
 	if b.l1Cfg != nil {
-		l1, err = newRistretto[K, V](*b.l1Cfg, b.keyFn)
+		l1raw, err = newRistretto[K, V](*b.l1Cfg, b.keyFn)
 		if err != nil {
 			return nil, fmt.Errorf("cache: init L1: %w", err)
 		}
+		l1raw.codec = b.codec
+		l1raw.ttlPolicy = ttlPolicy
+		l1raw.costFn = b.costFn
+		if b.l2Cfg != nil {
+			l1raw.ttlLimit = b.l1Cfg.TTL
+		}
+		if l1raw.codec == nil && b.l2Cfg != nil {
+			l1raw.codec = b.l2Cfg.Codec
+		}
+		l1 = l1raw
 		if b.stats != nil {
 			l1 = newInstrumentedCache(l1, b.stats, name, "l1")
 		}
 	}
 
 	if b.l2Cfg != nil {
-		var l2chain Cache[K, V] = newRedisCache(*b.l2Cfg, b.keyFn)
+		l2raw := newRedisCache(*b.l2Cfg, b.keyFn)
+		l2raw.ttlPolicy = ttlPolicy
+		var l2chain Cache[K, V] = l2raw
+		if tracer != nil {
+			l2chain = newTracedCache(l2chain, tracer, name, "l2")
+		}
 		if b.cbCfg != nil {
 			l2chain = newCircuitBreaker(l2chain, *b.cbCfg)
 		}
@@ -145,20 +264,59 @@ func (b *Builder[K, V]) Build() (Cache[K, V], error) {
 	var result Cache[K, V]
 	switch {
 	case l1 != nil && l2 != nil:
-		result = &multiLevel[K, V]{l1: l1, l2: l2}
+		ml := &multiLevel[K, V]{l1: l1, l1raw: l1raw, l2: l2}
+		if b.invCfg != nil {
+			ml.inv, err = b.buildInvalidation(l1raw)
+			if err != nil {
+				_ = ml.Close()
+				return nil, err
+			}
+		}
+		if b.hotCfg != nil {
+			ml.hot = newHotKeys(*b.hotCfg, l1, l2, b.keyFn, b.stats, name)
+		}
+		result = ml
 	case l1 != nil:
 		result = l1
 	default:
 		result = l2
 	}
 
-	if b.loader != nil {
-		result = newLoadingCache(result, b.loader, b.keyFn, b.loadCfg)
+	if b.loader != nil || b.batchFn != nil {
+		result = newLoadingCache(result,
+			instrumentLoader(b.loader, b.stats, tracer, name),
+			instrumentBatchLoader(b.batchFn, b.stats, tracer, name),
+			b.keyFn, b.loadCfg)
 	}
 
 	return result, nil
 }
 
+func (b *Builder[K, V]) buildInvalidation(l1 *ristrettoCache[K, V]) (*invalidationBus[K, V], error) {
+	cfg := *b.invCfg
+	if cfg.Client == nil {
+		cfg.Client = b.l2Cfg.Client
+	}
+	if cfg.Client == nil {
+		return nil, errors.New("cache: invalidation needs a Redis client, set one in the invalidation or L2 config")
+	}
+	if cfg.Channel == "" {
+		name := b.name
+		if name == "" {
+			name = b.l2Cfg.Prefix
+		}
+		if name == "" {
+			return nil, errors.New("cache: invalidation needs Name or L2 Prefix to derive the channel")
+		}
+		cfg.Channel = invalidationChannelPrefix + name
+	}
+	bus, err := newInvalidationBus(cfg, l1, b.keyFn, b.stats, b.name)
+	if err != nil {
+		return nil, fmt.Errorf("cache: init invalidation: %w", err)
+	}
+	return bus, nil
+}
+
 // MustBuild is like Build but panics on error. Suitable for package-level init.
 func (b *Builder[K, V]) MustBuild() Cache[K, V] {
 	c, err := b.Build()
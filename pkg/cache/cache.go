@@ -16,6 +16,19 @@ type Cache[K comparable, V any] interface {
 	SetTTL(ctx context.Context, key K, value V, ttl time.Duration) error
 	// Delete removes the entry from all layers.
 	Delete(ctx context.Context, key K) error
+	// GetMany returns the cached values for keys in as few round trips as the
+	// layer allows. Missing keys are absent from the result. On error the
+	// result may still hold the entries that were read successfully.
+	GetMany(ctx context.Context, keys []K) (map[K]V, error)
+	// SetMany stores all entries using the layer's configured TTL.
+	SetMany(ctx context.Context, entries map[K]V) error
+	// DeleteMany removes the entries from all layers.
+	DeleteMany(ctx context.Context, keys []K) error
+	// SetTagged stores the value using the layer's configured TTL and
+	// associates the key with every tag, for later group invalidation.
+	SetTagged(ctx context.Context, key K, value V, tags ...string) error
+	// InvalidateTag removes every entry associated with tag from all layers.
+	InvalidateTag(ctx context.Context, tag string) error
 	// Close releases resources held by the cache.
 	Close() error
 }
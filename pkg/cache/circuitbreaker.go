@@ -136,4 +136,74 @@ func (c *circuitBreaker[K, V]) Delete(ctx context.Context, key K) error {
 	return nil
 }
 
+func (c *circuitBreaker[K, V]) GetMany(ctx context.Context, keys []K) (map[K]V, error) {
+	if !c.allow() {
+		return make(map[K]V), nil
+	}
+	found, err := c.inner.GetMany(ctx, keys)
+	if err != nil {
+		c.recordFailure()
+		return found, err
+	}
+	c.recordSuccess()
+	return found, nil
+}
+
+func (c *circuitBreaker[K, V]) SetMany(ctx context.Context, entries map[K]V) error {
+	if !c.allow() {
+		return nil
+	}
+	err := c.inner.SetMany(ctx, entries)
+	if err != nil {
+		c.recordFailure()
+		return err
+	}
+	c.recordSuccess()
+	return nil
+}
+
+func (c *circuitBreaker[K, V]) DeleteMany(ctx context.Context, keys []K) error {
+	if !c.allow() {
+		return nil
+	}
+	err := c.inner.DeleteMany(ctx, keys)
+	if err != nil {
+		c.recordFailure()
+		return err
+	}
+	c.recordSuccess()
+	return nil
+}
+
+func (c *circuitBreaker[K, V]) SetTagged(ctx context.Context, key K, value V, tags ...string) error {
+	if !c.allow() {
+		return nil
+	}
+	err := c.inner.SetTagged(ctx, key, value, tags...)
+	if err != nil {
+		c.recordFailure()
+		return err
+	}
+	c.recordSuccess()
+	return nil
+}
+
+func (c *circuitBreaker[K, V]) InvalidateTag(ctx context.Context, tag string) error {
+	_, err := c.invalidateTagKeys(ctx, tag)
+	return err
+}
+
+func (c *circuitBreaker[K, V]) invalidateTagKeys(ctx context.Context, tag string) ([]string, error) {
+	if !c.allow() {
+		return nil, nil
+	}
+	keys, err := invalidateTagKeys(ctx, c.inner, tag)
+	if err != nil {
+		c.recordFailure()
+		return keys, err
+	}
+	c.recordSuccess()
+	return keys, nil
+}
+
 func (c *circuitBreaker[K, V]) Close() error { return c.inner.Close() }
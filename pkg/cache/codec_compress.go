@@ -4,6 +4,10 @@ import (
 	"bytes"
 	"compress/gzip"
 	"io"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
 )
 
 // Compressed wraps a Codec with gzip compression (BestSpeed level).
@@ -59,3 +63,81 @@ func (c compressedCodec[V]) Unmarshal(data []byte) (V, error) {
 	}
 	return c.inner.Unmarshal(raw)
 }
+
+// Zstd wraps a Codec with zstd compression (fastest level).
+// Compresses better than Compressed at a similar or lower CPU cost; prefer it
+// for new caches. Payloads are not interchangeable with gzip or snappy ones.
+//
+// Example:
+//
+//	cache.RedisConfig[*pb.Contact]{
+//	    Codec: cache.Zstd(cache.Proto(func() *pb.Contact { return &pb.Contact{} })),
+//	}
+func Zstd[V any](inner Codec[V]) Codec[V] {
+	return zstdCodec[V]{inner: inner}
+}
+
+// The zstd encoder and decoder are safe for concurrent EncodeAll/DecodeAll
+// and expensive to create, so a single pair is shared by all caches.
+var zstdCoders = sync.OnceValues(func() (*zstd.Encoder, *zstd.Decoder) {
+	enc, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+	dec, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	return enc, dec
+})
+
+type zstdCodec[V any] struct {
+	inner Codec[V]
+}
+
+func (c zstdCodec[V]) Marshal(v V) ([]byte, error) {
+	raw, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	enc, _ := zstdCoders()
+	return enc.EncodeAll(raw, nil), nil
+}
+
+func (c zstdCodec[V]) Unmarshal(data []byte) (V, error) {
+	_, dec := zstdCoders()
+	raw, err := dec.DecodeAll(data, nil)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return c.inner.Unmarshal(raw)
+}
+
+// Snappy wraps a Codec with snappy block compression.
+// Lowest CPU overhead of the compression codecs at a lower ratio; suited to
+// hot, latency-sensitive caches with medium-sized values.
+//
+// Example:
+//
+//	cache.RedisConfig[*pb.Contact]{
+//	    Codec: cache.Snappy(cache.Proto(func() *pb.Contact { return &pb.Contact{} })),
+//	}
+func Snappy[V any](inner Codec[V]) Codec[V] {
+	return snappyCodec[V]{inner: inner}
+}
+
+type snappyCodec[V any] struct {
+	inner Codec[V]
+}
+
+func (c snappyCodec[V]) Marshal(v V) ([]byte, error) {
+	raw, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, raw), nil
+}
+
+func (c snappyCodec[V]) Unmarshal(data []byte) (V, error) {
+	raw, err := snappy.Decode(nil, data)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return c.inner.Unmarshal(raw)
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Encrypted wraps a Codec with AES-GCM encryption, so values such as tokens
+// or PII never reach Redis in plaintext.
+//
+// Every payload records the ID of the key that encrypted it. New writes use
+// keyring.Current(); reads look the key up by ID, so entries written before a
+// rotation stay readable as long as the old key remains in the keyring, and
+// stop decrypting once it is removed.
+// The key ID and header are authenticated along with the value.
+//
+// Encrypted goes inside Envelope and outside compression — ciphertext does
+// not compress: Envelope(Encrypted(Zstd(Proto(...)), keyring)).
+//
+// Example:
+//
+//	keyring, err := cache.NewFileKeyring("/etc/secrets/cache-keys.json")
+//	...
+//	cache.RedisConfig[*pb.Token]{
+//	    Codec: cache.Encrypted(cache.Proto(func() *pb.Token { return &pb.Token{} }), keyring),
+//	}
+func Encrypted[V any](inner Codec[V], keyring Keyring) Codec[V] {
+	return &encryptedCodec[V]{inner: inner, keyring: keyring}
+}
+
+// encryptedMagic starts every encrypted payload; see envelopeMagic.
+var encryptedMagic = []byte{0x00, 'w', 'c', 'x'}
+
+const (
+	encryptedFormatV1 byte = 1
+	maxKeyIDLen            = 255
+)
+
+var errEncryptedFormat = errors.New("cache: malformed encrypted payload")
+
+type encryptedCodec[V any] struct {
+	inner   Codec[V]
+	keyring Keyring
+
+	// aeads caches one cipher per key ID. The key is still looked up in the
+	// keyring on every call, so a key removed from it stops decrypting.
+	aeads sync.Map // string → cachedAEAD
+}
+
+type cachedAEAD struct {
+	key  []byte
+	aead cipher.AEAD
+}
+
+// Payload layout: magic | format | len(id) | id | nonce | ciphertext.
+// Everything before the nonce is passed to GCM as additional data.
+func (c *encryptedCodec[V]) Marshal(v V) ([]byte, error) {
+	raw, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	id, key, err := c.keyring.Current()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := c.aead(id, key)
+	if err != nil {
+		return nil, err
+	}
+
+	headerLen := len(encryptedMagic) + 2 + len(id)
+	buf := make([]byte, headerLen+aead.NonceSize(), headerLen+aead.NonceSize()+len(raw)+aead.Overhead())
+	copy(buf, encryptedMagic)
+	buf[4] = encryptedFormatV1
+	buf[5] = byte(len(id))
+	copy(buf[6:], id)
+
+	nonce := buf[headerLen:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(buf, nonce, raw, buf[:headerLen]), nil
+}
+
+func (c *encryptedCodec[V]) Unmarshal(data []byte) (V, error) {
+	var zero V
+	if len(data) < 6 || !bytes.HasPrefix(data, encryptedMagic) || data[4] != encryptedFormatV1 {
+		return zero, errEncryptedFormat
+	}
+	headerLen := 6 + int(data[5])
+	if len(data) < headerLen {
+		return zero, errEncryptedFormat
+	}
+	id := string(data[6:headerLen])
+
+	key, err := c.keyring.Key(id)
+	if err != nil {
+		return zero, err
+	}
+	aead, err := c.aead(id, key)
+	if err != nil {
+		return zero, err
+	}
+	if len(data) < headerLen+aead.NonceSize() {
+		return zero, errEncryptedFormat
+	}
+	nonce := data[headerLen : headerLen+aead.NonceSize()]
+	raw, err := aead.Open(nil, nonce, data[headerLen+aead.NonceSize():], data[:headerLen])
+	if err != nil {
+		return zero, fmt.Errorf("cache: decrypt with key %q: %w", id, err)
+	}
+	return c.inner.Unmarshal(raw)
+}
+
+// aead returns the cipher for key, the key the keyring currently holds under
+// id. The cached cipher is reused only while the key is unchanged.
+func (c *encryptedCodec[V]) aead(id string, key []byte) (cipher.AEAD, error) {
+	if a, ok := c.aeads.Load(id); ok && bytes.Equal(a.(cachedAEAD).key, key) {
+		return a.(cachedAEAD).aead, nil
+	}
+	if len(id) > maxKeyIDLen {
+		return nil, fmt.Errorf("cache: key ID %q is longer than %d bytes", id, maxKeyIDLen)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cache: key %q: %w", id, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	c.aeads.Store(id, cachedAEAD{key: bytes.Clone(key), aead: aead})
+	return aead, nil
+}
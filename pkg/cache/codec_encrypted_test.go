@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptedCodec(t *testing.T) {
+	oldKey, newKey := bytes.Repeat([]byte{1}, 16), bytes.Repeat([]byte{2}, 32)
+	before, err := StaticKeyring("k1", map[string][]byte{"k1": oldKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := StaticKeyring("k2", map[string][]byte{"k1": oldKey, "k2": newKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dropped, err := StaticKeyring("k2", map[string][]byte{"k2": newKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := Encrypted(RawString(), before).Marshal("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, []byte("secret")) {
+		t.Fatal("payload contains the plaintext")
+	}
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 0xFF
+	relabeled := append([]byte(nil), sealed...)
+	relabeled[6] = 'x' // key ID k1 → x1, which is authenticated
+
+	tests := []struct {
+		name    string
+		keyring Keyring
+		data    []byte
+		want    string
+		wantErr error // with fail set, nil accepts any error
+		fail    bool
+	}{
+		{name: "same keyring", keyring: before, data: sealed, want: "secret"},
+		{name: "after rotation", keyring: rotated, data: sealed, want: "secret"},
+		{name: "old key dropped", keyring: dropped, data: sealed, wantErr: ErrUnknownKey, fail: true},
+		{name: "tampered ciphertext", keyring: before, data: tampered, fail: true},
+		{name: "tampered key ID", keyring: rotated, data: relabeled, fail: true},
+		{name: "plaintext payload", keyring: before, data: []byte("secret"), wantErr: errEncryptedFormat, fail: true},
+		{name: "truncated header", keyring: before, data: sealed[:7], wantErr: errEncryptedFormat, fail: true},
+		{name: "truncated nonce", keyring: before, data: sealed[:10], wantErr: errEncryptedFormat, fail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encrypted(RawString(), tt.keyring).Unmarshal(tt.data)
+			if tt.fail {
+				if err == nil {
+					t.Fatalf("Unmarshal = %q, want error", got)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Unmarshal error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncryptedCodecUsesCurrentKey(t *testing.T) {
+	keyring, err := StaticKeyring("k2", map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 16),
+		"k2": bytes.Repeat([]byte{2}, 24),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codec := Encrypted(RawString(), keyring)
+	a, err := codec.Marshal("v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := codec.Marshal("v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := string(a[6 : 6+int(a[5])]); id != "k2" {
+		t.Errorf("key ID = %q, want the current k2", id)
+	}
+	if bytes.Equal(a, b) {
+		t.Error("two payloads of the same value should differ by nonce")
+	}
+}
+
+func TestEncryptedCodecRemovedKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	writeKeys := func(current string, keys map[string][]byte) {
+		t.Helper()
+		file := fileKeyringJSON{Current: current, Keys: map[string]string{}}
+		for id, key := range keys {
+			file.Keys[id] = base64.StdEncoding.EncodeToString(key)
+		}
+		data, err := json.Marshal(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldKey, newKey := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	writeKeys("k1", map[string][]byte{"k1": oldKey})
+	keyring, err := NewFileKeyring(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codec := Encrypted(RawString(), keyring)
+	sealed, err := codec.Marshal("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := codec.Unmarshal(sealed); err != nil {
+		t.Fatalf("Unmarshal before removal: %v", err)
+	}
+
+	// k1 is compromised: it is dropped from the file and the keyring reloaded.
+	writeKeys("k2", map[string][]byte{"k2": newKey})
+	if err := keyring.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := codec.Unmarshal(sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Unmarshal with a removed key = %q, %v; want ErrUnknownKey", got, err)
+	}
+}
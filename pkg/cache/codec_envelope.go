@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Envelope wraps a Codec so that every L2 payload carries a small metadata
+// header: the envelope format version, the time the value was written, and
+// how long the loader took to produce it.
+//
+// With the header in Redis, stale-while-revalidate (LoadingConfig.SoftTTL)
+// and probabilistic early refresh (LoadingConfig.EarlyRefreshBeta) work for
+// values read from L2 — including values written by other replicas or by
+// this process before a restart — not only for values loaded locally.
+//
+// Envelope must be the outermost codec, e.g. Envelope(Compressed(Proto(...))).
+// Payloads without the header (written before Envelope was enabled) are
+// decoded with the inner codec and treated as having no metadata.
+//
+// Example:
+//
+//	cache.RedisConfig[*pb.Contact]{
+//	    Codec: cache.Envelope(cache.Proto(func() *pb.Contact { return &pb.Contact{} })),
+//	}
+func Envelope[V any](inner Codec[V]) Codec[V] {
+	return envelopeCodec[V]{inner: inner}
+}
+
+// envelopeMagic starts every envelope. The leading zero byte cannot begin a
+// valid protobuf field tag, a JSON document, or a gzip stream.
+var envelopeMagic = []byte{0x00, 'w', 'c', 'e'}
+
+const (
+	envelopeFormatV1 byte = 1
+	// magic + format version + written-at unix nanos + load duration nanos.
+	envelopeHeaderLen = 4 + 1 + 8 + 8
+)
+
+var errEnvelopeFormat = errors.New("cache: unsupported envelope format")
+
+// entryMeta is the metadata stored next to a payload by the Envelope codec.
+type entryMeta struct {
+	WrittenAt time.Time
+	// Delta is how long the loader took to produce the value.
+	// Used as the recompute cost in XFetch early expiration.
+	Delta time.Duration
+}
+
+// metaCodec is implemented by codecs that persist entryMeta next to the payload.
+// redisCache uses it instead of Marshal/Unmarshal when available.
+type metaCodec[V any] interface {
+	marshalMeta(v V, meta entryMeta) ([]byte, error)
+	unmarshalMeta(data []byte) (V, entryMeta, error)
+}
+
+type envelopeCodec[V any] struct {
+	inner Codec[V]
+}
+
+func (c envelopeCodec[V]) Marshal(v V) ([]byte, error) {
+	return c.marshalMeta(v, entryMeta{WrittenAt: time.Now()})
+}
+
+func (c envelopeCodec[V]) Unmarshal(data []byte) (V, error) {
+	v, _, err := c.unmarshalMeta(data)
+	return v, err
+}
+
+func (c envelopeCodec[V]) marshalMeta(v V, meta entryMeta) ([]byte, error) {
+	raw, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, envelopeHeaderLen, envelopeHeaderLen+len(raw))
+	copy(buf, envelopeMagic)
+	buf[4] = envelopeFormatV1
+	binary.BigEndian.PutUint64(buf[5:], uint64(meta.WrittenAt.UnixNano()))
+	binary.BigEndian.PutUint64(buf[13:], uint64(meta.Delta))
+	return append(buf, raw...), nil
+}
+
+func (c envelopeCodec[V]) unmarshalMeta(data []byte) (V, entryMeta, error) {
+	if len(data) < envelopeHeaderLen || !bytes.HasPrefix(data, envelopeMagic) {
+		v, err := c.inner.Unmarshal(data)
+		return v, entryMeta{}, err
+	}
+	if data[4] != envelopeFormatV1 {
+		var zero V
+		return zero, entryMeta{}, errEnvelopeFormat
+	}
+	meta := entryMeta{
+		WrittenAt: time.Unix(0, int64(binary.BigEndian.Uint64(data[5:]))),
+		Delta:     time.Duration(binary.BigEndian.Uint64(data[13:])),
+	}
+	v, err := c.inner.Unmarshal(data[envelopeHeaderLen:])
+	return v, meta, err
+}
+
+// Entry metadata travels through the layer stack on the context, so the
+// circuit breaker, instrumentation, and multilevel wrappers need no changes:
+//
+//   - loadingCache attaches a metaSink before reading; redisCache records the
+//     metadata of every enveloped payload it decodes into it.
+//   - loadingCache attaches the load duration before writing; redisCache
+//     stores it in the envelope.
+type (
+	ctxKeyMetaSink  struct{}
+	ctxKeyLoadDelta struct{}
+)
+
+// metaSink collects entryMeta per serialized key during a read.
+type metaSink struct {
+	mu sync.Mutex
+	m  map[string]entryMeta
+}
+
+func withMetaSink(ctx context.Context) (context.Context, *metaSink) {
+	sink := &metaSink{}
+	return context.WithValue(ctx, ctxKeyMetaSink{}, sink), sink
+}
+
+func metaSinkFrom(ctx context.Context) *metaSink {
+	sink, _ := ctx.Value(ctxKeyMetaSink{}).(*metaSink)
+	return sink
+}
+
+func (s *metaSink) record(keyStr string, meta entryMeta) {
+	if meta.WrittenAt.IsZero() {
+		return
+	}
+	s.mu.Lock()
+	if s.m == nil {
+		s.m = make(map[string]entryMeta)
+	}
+	s.m[keyStr] = meta
+	s.mu.Unlock()
+}
+
+func (s *metaSink) get(keyStr string) (entryMeta, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	meta, ok := s.m[keyStr]
+	return meta, ok
+}
+
+func withLoadDelta(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, ctxKeyLoadDelta{}, d)
+}
+
+func loadDeltaFrom(ctx context.Context) time.Duration {
+	d, _ := ctx.Value(ctxKeyLoadDelta{}).(time.Duration)
+	return d
+}
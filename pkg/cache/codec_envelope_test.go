@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEnvelopeCodec(t *testing.T) {
+	codec := Envelope(RawString()).(envelopeCodec[string])
+	writtenAt := time.Unix(1_700_000_000, 123)
+	enveloped, err := codec.marshalMeta("payload", entryMeta{WrittenAt: writtenAt, Delta: 250 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	badFormat := append([]byte(nil), enveloped...)
+	badFormat[4] = envelopeFormatV1 + 1
+
+	tests := []struct {
+		name     string
+		data     []byte
+		want     string
+		wantMeta entryMeta
+		wantErr  error
+	}{
+		{
+			name:     "enveloped",
+			data:     enveloped,
+			want:     "payload",
+			wantMeta: entryMeta{WrittenAt: writtenAt, Delta: 250 * time.Millisecond},
+		},
+		{name: "legacy payload without header", data: []byte("legacy"), want: "legacy"},
+		{name: "short payload starting like the magic", data: envelopeMagic[:3], want: string(envelopeMagic[:3])},
+		{name: "unknown format version", data: badFormat, wantErr: errEnvelopeFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, meta, err := codec.unmarshalMeta(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unmarshalMeta error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("value = %q, want %q", got, tt.want)
+			}
+			if !meta.WrittenAt.Equal(tt.wantMeta.WrittenAt) || meta.Delta != tt.wantMeta.Delta {
+				t.Errorf("meta = %+v, want %+v", meta, tt.wantMeta)
+			}
+		})
+	}
+}
+
+func TestEnvelopeCodecMarshalStampsWriteTime(t *testing.T) {
+	codec := Envelope(RawString())
+	before := time.Now()
+	data, err := codec.Marshal("v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, meta, err := codec.(envelopeCodec[string]).unmarshalMeta(data)
+	if err != nil || v != "v" {
+		t.Fatalf("unmarshalMeta = %q, %v", v, err)
+	}
+	if meta.WrittenAt.Before(before) || meta.WrittenAt.After(time.Now()) {
+		t.Errorf("WrittenAt %v is not the time of Marshal", meta.WrittenAt)
+	}
+	if got, err := codec.Unmarshal(data); err != nil || got != "v" {
+		t.Errorf("Unmarshal = %q, %v", got, err)
+	}
+}
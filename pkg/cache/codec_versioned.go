@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrVersionMismatch is returned by a Versioned codec when a payload was
+// written with a different schema version (or without a version header).
+// The L2 layer treats it as a miss rather than an error, so it is neither
+// counted by Stats.Error nor by the circuit breaker.
+var ErrVersionMismatch = errors.New("cache: payload version mismatch")
+
+// Versioned wraps a Codec so that every payload is prefixed with a schema
+// version. On read, a payload with another version is reported as
+// ErrVersionMismatch and served as a cache miss: the loader recomputes the
+// value and overwrites the entry in the current version.
+//
+// Bump version whenever the shape of V changes incompatibly. During a rolling
+// deploy old and new pods then each see the other's entries as misses instead
+// of failing to decode them.
+//
+// Payloads written before Versioned was enabled carry no header and are also
+// served as misses. Versioned goes inside Envelope and may wrap or be wrapped
+// by a compression codec.
+//
+// Example:
+//
+//	cache.RedisConfig[*pb.Contact]{
+//	    Codec: cache.Versioned(cache.Proto(func() *pb.Contact { return &pb.Contact{} }), 2),
+//	}
+func Versioned[V any](inner Codec[V], version uint32) Codec[V] {
+	return versionedCodec[V]{inner: inner, version: version}
+}
+
+// versionedMagic starts every versioned payload; see envelopeMagic.
+var versionedMagic = []byte{0x00, 'w', 'c', 'v'}
+
+// magic + version.
+const versionedHeaderLen = 4 + 4
+
+type versionedCodec[V any] struct {
+	inner   Codec[V]
+	version uint32
+}
+
+func (c versionedCodec[V]) Marshal(v V) ([]byte, error) {
+	raw, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, versionedHeaderLen, versionedHeaderLen+len(raw))
+	copy(buf, versionedMagic)
+	binary.BigEndian.PutUint32(buf[4:], c.version)
+	return append(buf, raw...), nil
+}
+
+func (c versionedCodec[V]) Unmarshal(data []byte) (V, error) {
+	if len(data) < versionedHeaderLen || !bytes.HasPrefix(data, versionedMagic) {
+		var zero V
+		return zero, fmt.Errorf("%w: no version header", ErrVersionMismatch)
+	}
+	if got := binary.BigEndian.Uint32(data[4:]); got != c.version {
+		var zero V
+		return zero, fmt.Errorf("%w: got %d, want %d", ErrVersionMismatch, got, c.version)
+	}
+	return c.inner.Unmarshal(data[versionedHeaderLen:])
+}
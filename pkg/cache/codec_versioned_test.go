@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVersionedCodec(t *testing.T) {
+	v2 := Versioned(RawString(), 2)
+	current, err := v2.Marshal("payload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	older, err := Versioned(RawString(), 1).Marshal("payload")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		want     string
+		mismatch bool
+	}{
+		{name: "current version", data: current, want: "payload"},
+		{name: "other version", data: older, mismatch: true},
+		{name: "no header", data: []byte("payload written before Versioned"), mismatch: true},
+		{name: "truncated header", data: current[:versionedHeaderLen-1], mismatch: true},
+		{name: "empty", data: nil, mismatch: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v2.Unmarshal(tt.data)
+			if tt.mismatch {
+				if !errors.Is(err, ErrVersionMismatch) {
+					t.Fatalf("Unmarshal error = %v, want ErrVersionMismatch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionedCodecInsideEnvelope(t *testing.T) {
+	codec := Envelope(Versioned(JSON[[]int](), 3))
+	data, err := codec.Marshal([]int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := codec.Unmarshal(data)
+	if err != nil || len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("Unmarshal = %v, %v; want [1 2]", got, err)
+	}
+
+	_, err = Envelope(Versioned(JSON[[]int](), 4)).Unmarshal(data)
+	if !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("Unmarshal with a bumped version: want ErrVersionMismatch, got %v", err)
+	}
+}
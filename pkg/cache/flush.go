@@ -0,0 +1,165 @@
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Flush removes every entry of c from all layers: L1 is cleared, L2 keys
+// under the cache Prefix are deleted with SCAN + UNLINK, and, with
+// WithInvalidation, every other process clears its L1 as well. A loading
+// cache also forgets its negative and soft-TTL state.
+//
+// Flush is meant for rare resyncs — e.g. after invalidation events may have
+// been lost — not for regular use: the next reads all go to the source.
+func Flush[K comparable, V any](ctx context.Context, c Cache[K, V]) error {
+	if f, ok := c.(flusher); ok {
+		return f.flush(ctx)
+	}
+	return nil
+}
+
+// flusher is implemented by every layer and wrapper built by Builder.
+type flusher interface {
+	flush(ctx context.Context) error
+}
+
+// flushLocal clears only the in-process state of c: L1 and, for a loading
+// cache, its negative and soft-TTL state. L2 is not touched and nothing is
+// published to other processes.
+func flushLocal[K comparable, V any](ctx context.Context, c Cache[K, V]) error {
+	if f, ok := c.(localFlusher); ok {
+		return f.flushLocal(ctx)
+	}
+	return nil
+}
+
+// localFlusher is implemented by every layer and wrapper that holds, or
+// wraps, in-process state. Redis has none.
+type localFlusher interface {
+	flushLocal(ctx context.Context) error
+}
+
+func (c *ristrettoCache[K, V]) flush(context.Context) error {
+	c.clear()
+	return nil
+}
+
+func (c *ristrettoCache[K, V]) flushLocal(ctx context.Context) error { return c.flush(ctx) }
+
+// flushScanCount is the SCAN COUNT hint and the UNLINK batch size used by Flush.
+const flushScanCount = 500
+
+func (c *redisCache[K, V]) flush(ctx context.Context) error {
+	match := redisGlobEscape(c.prefix) + ":*"
+	// SCAN only walks the node it is sent to, so sharded clients scan each.
+	switch client := c.client.(type) {
+	case *redis.ClusterClient:
+		return client.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return flushMatching(ctx, node, match)
+		})
+	case *redis.Ring:
+		return client.ForEachShard(ctx, func(ctx context.Context, shard *redis.Client) error {
+			return flushMatching(ctx, shard, match)
+		})
+	}
+	return flushMatching(ctx, c.client, match)
+}
+
+func flushMatching(ctx context.Context, client redis.UniversalClient, match string) error {
+	iter := client.Scan(ctx, 0, match, flushScanCount).Iterator()
+	batch := make([]string, 0, flushScanCount)
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == flushScanCount {
+			if err := client.Unlink(ctx, batch...).Err(); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return client.Unlink(ctx, batch...).Err()
+	}
+	return nil
+}
+
+// redisGlobEscape escapes the glob metacharacters of a SCAN MATCH pattern.
+func redisGlobEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(s)
+}
+
+func (c *multiLevel[K, V]) flush(ctx context.Context) error {
+	if err := Flush(ctx, c.l1); err != nil {
+		return err
+	}
+	if err := Flush(ctx, c.l2); err != nil {
+		return err
+	}
+	if c.inv != nil {
+		c.inv.publishFlush()
+	}
+	return nil
+}
+
+func (c *multiLevel[K, V]) flushLocal(ctx context.Context) error { return flushLocal(ctx, c.l1) }
+
+func (c *loadingCache[K, V]) flush(ctx context.Context) error {
+	if c.negative != nil {
+		_ = Flush(ctx, c.negative)
+	}
+	c.softExpiry.Clear()
+	return Flush(ctx, c.cache)
+}
+
+func (c *loadingCache[K, V]) flushLocal(ctx context.Context) error {
+	if c.negative != nil {
+		_ = flushLocal(ctx, c.negative)
+	}
+	c.softExpiry.Clear()
+	return flushLocal(ctx, c.cache)
+}
+
+func (c *instrumentedCache[K, V]) flush(ctx context.Context) error {
+	if err := Flush(ctx, c.inner); err != nil {
+		c.stats.Error(ctx, c.name, c.layer, "delete")
+		return err
+	}
+	return nil
+}
+
+func (c *instrumentedCache[K, V]) flushLocal(ctx context.Context) error {
+	return flushLocal(ctx, c.inner)
+}
+
+func (c *circuitBreaker[K, V]) flush(ctx context.Context) error { return Flush(ctx, c.inner) }
+
+func (c *circuitBreaker[K, V]) flushLocal(ctx context.Context) error {
+	return flushLocal(ctx, c.inner)
+}
+
+func (c *tracedCache[K, V]) flush(ctx context.Context) error {
+	ctx, span := c.start(ctx, "Flush")
+	err := Flush(ctx, c.inner)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedCache[K, V]) flushLocal(ctx context.Context) error {
+	return flushLocal(ctx, c.inner)
+}
+
+func (c *noopCache[K, V]) flush(context.Context) error {
+	c.mu.Lock()
+	clear(c.m)
+	clear(c.tags)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *noopCache[K, V]) flushLocal(ctx context.Context) error { return c.flush(ctx) }
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"path"
+	"testing"
+)
+
+func TestRedisGlobEscape(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{prefix: "contacts", want: `contacts`},
+		{prefix: "{contacts}", want: `{contacts}`},
+		{prefix: "a*b", want: `a\*b`},
+		{prefix: "a?b", want: `a\?b`},
+		{prefix: "[ab]", want: `\[ab\]`},
+		{prefix: `a\b`, want: `a\\b`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got := redisGlobEscape(tt.prefix)
+			if got != tt.want {
+				t.Errorf("redisGlobEscape(%q) = %q, want %q", tt.prefix, got, tt.want)
+			}
+			// SCAN MATCH follows the glob syntax of path.Match, so the
+			// escaped prefix matches its own keys and nothing else.
+			if ok, err := path.Match(got+":*", tt.prefix+":1"); err != nil || !ok {
+				t.Errorf("pattern %q does not match key %q: %v", got+":*", tt.prefix+":1", err)
+			}
+			if ok, _ := path.Match(got+":*", "other:1"); ok {
+				t.Errorf("pattern %q matches a key of another prefix", got+":*")
+			}
+		})
+	}
+}
@@ -3,80 +3,58 @@ package cache
 import (
 	"context"
 	"math/rand/v2"
-	"sync"
 	"time"
 )
 
-// GetMany fetches multiple keys concurrently.
+// GetMany fetches multiple keys via Cache.GetMany.
 // Returns a map of found entries and a slice of keys that were not in cache.
-// All gets run in parallel; the first error aborts remaining work and is returned.
+// On error, found holds whatever the cache managed to read and missed lists the rest.
 func GetMany[K comparable, V any](ctx context.Context, c Cache[K, V], keys []K) (found map[K]V, missed []K, err error) {
-	found = make(map[K]V, len(keys))
-	missed = make([]K, 0)
-
-	type result struct {
-		key K
-		val V
-		ok  bool
-		err error
+	found, err = c.GetMany(ctx, keys)
+	if found == nil {
+		found = make(map[K]V)
 	}
-
-	results := make(chan result, len(keys))
-	for _, key := range keys {
-		go func() {
-			v, ok, e := c.Get(ctx, key)
-			results <- result{key: key, val: v, ok: ok, err: e}
-		}()
-	}
-
-	var mu sync.Mutex
-	for range keys {
-		r := <-results
-		if r.err != nil {
-			err = r.err
-			continue
-		}
-		mu.Lock()
-		if r.ok {
-			found[r.key] = r.val
-		} else {
-			missed = append(missed, r.key)
+	missed = make([]K, 0)
+	for _, k := range keys {
+		if _, ok := found[k]; !ok {
+			missed = append(missed, k)
 		}
-		mu.Unlock()
 	}
 	return found, missed, err
 }
 
-// SetMany stores multiple key-value pairs concurrently.
-// Returns the first error encountered; other writes are not aborted.
+// SetMany stores multiple key-value pairs via Cache.SetMany.
 func SetMany[K comparable, V any](ctx context.Context, c Cache[K, V], entries map[K]V) error {
-	errc := make(chan error, len(entries))
-	for k, v := range entries {
-		go func() { errc <- c.Set(ctx, k, v) }()
-	}
-	var first error
-	for range entries {
-		if e := <-errc; e != nil && first == nil {
-			first = e
-		}
-	}
-	return first
+	return c.SetMany(ctx, entries)
 }
 
-// DeleteMany removes multiple keys concurrently.
-// Returns the first error encountered; other deletes are not aborted.
+// DeleteMany removes multiple keys via Cache.DeleteMany.
 func DeleteMany[K comparable, V any](ctx context.Context, c Cache[K, V], keys []K) error {
-	errc := make(chan error, len(keys))
-	for _, k := range keys {
-		go func() { errc <- c.Delete(ctx, k) }()
+	return c.DeleteMany(ctx, keys)
+}
+
+// GetOrLoad returns the value for key, loading it through the cache's
+// LoadFunc on a miss. A non-zero ttl overrides the configured TTLs for the
+// value stored by this load (see WithLoadTTL).
+// Returns ErrNotFound when the key is absent from both cache and source, or
+// when c has no loader and the key is not cached.
+//
+// Example — short-lived entry for a value known to change soon:
+//
+//	v, err := cache.GetOrLoad(ctx, sessions, id, time.Minute)
+func GetOrLoad[K comparable, V any](ctx context.Context, c Cache[K, V], key K, ttl time.Duration) (V, error) {
+	if ttl > 0 {
+		ctx = WithLoadTTL(ctx, ttl)
 	}
-	var first error
-	for range keys {
-		if e := <-errc; e != nil && first == nil {
-			first = e
-		}
+	v, ok, err := c.Get(ctx, key)
+	if err != nil {
+		return v, err
+	}
+	if !ok {
+		var zero V
+		return zero, ErrNotFound
 	}
-	return first
+	return v, nil
 }
 
 // Jitter randomizes a TTL duration by ±fraction to prevent cache avalanche.
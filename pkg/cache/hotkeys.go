@@ -0,0 +1,411 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"math/bits"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2/z"
+)
+
+// HotKeyConfig configures hot-key detection for a multilevel cache.
+//
+// Every read is counted in a count-min sketch. The TopK keys with the highest
+// estimated frequency are reported each Window to Stats implementations that
+// also implement HotKeyStats, and are available through HotKeys.
+//
+// With PromoteTTL set, a hot key read from L2 is kept in L1 for PromoteTTL
+// instead of the L1 TTL, and re-read from L2 every RefreshInterval so the
+// longer-lived L1 copy stays fresh. Keys that are not hot keep the normal behaviour.
+type HotKeyConfig struct {
+	// TopK is the number of hottest keys tracked and reported. Default: 32.
+	TopK int
+	// Width is the number of counters per sketch row, rounded up to a power
+	// of two. Larger widths reduce over-estimation. Default: 16384.
+	Width int
+	// Window is the reporting interval. Counters are halved after every
+	// window, so a key stays hot only while it keeps being read. Default: 1m.
+	Window time.Duration
+	// MinCount is the estimated number of reads within roughly one Window a
+	// top key needs to be considered hot. Default: 100.
+	MinCount uint32
+	// PromoteTTL is the L1 TTL used for hot keys. Zero disables promotion.
+	PromoteTTL time.Duration
+	// RefreshInterval is how often promoted keys are re-read from L2.
+	// Default: 10s.
+	RefreshInterval time.Duration
+}
+
+const (
+	defaultHotKeyTopK            = 32
+	defaultHotKeyWidth           = 16384
+	defaultHotKeyWindow          = time.Minute
+	defaultHotKeyMinCount        = 100
+	defaultHotKeyRefreshInterval = 10 * time.Second
+	hotKeySketchDepth            = 4
+)
+
+// HotKey is a frequently read key and its estimated read count, decayed over
+// recent windows.
+type HotKey struct {
+	Key   string // serialized with the cache KeyFunc
+	Count uint32
+}
+
+// HotKeyStats is an optional extension of Stats. When the Stats passed to
+// Builder.WithStats implements it, the hot keys of every cache built
+// WithHotKeys are reported once per HotKeyConfig.Window, hottest first.
+type HotKeyStats interface {
+	HotKeys(ctx context.Context, name string, keys []HotKey)
+}
+
+// HotKeys returns the current hot keys of c, hottest first.
+// Returns nil when c was not built WithHotKeys.
+func HotKeys[K comparable, V any](c Cache[K, V]) []HotKey {
+	if r, ok := c.(hotKeyReporter); ok {
+		return r.hotKeys()
+	}
+	return nil
+}
+
+// hotKeyReporter is implemented by multiLevel and the wrappers that can reach it.
+type hotKeyReporter interface {
+	hotKeys() []HotKey
+}
+
+func (c *loadingCache[K, V]) hotKeys() []HotKey { return HotKeys(c.cache) }
+
+func (c *multiLevel[K, V]) hotKeys() []HotKey {
+	if c.hot == nil {
+		return nil
+	}
+	return c.hot.top()
+}
+
+// hotKeyTracker estimates key frequency with a count-min sketch and keeps
+// the TopK candidates. The candidate map is copy-on-write and the counts are
+// atomic, so record only takes mu to change the candidate set or to
+// recompute the floor after the coldest candidate was read.
+type hotKeyTracker[K comparable] struct {
+	rows     [hotKeySketchDepth][]atomic.Uint32
+	mask     uint64
+	topK     int
+	minCount uint32
+
+	// floor is the lowest count among the candidates once TopK are tracked;
+	// keys estimated at or below it cannot enter the top. coldest is the
+	// candidate holding it, nil while fewer than TopK are tracked.
+	floor   atomic.Uint32
+	coldest atomic.Pointer[hotCandidate[K]]
+
+	mu         sync.Mutex // serializes writers of candidates, floor and coldest
+	candidates atomic.Pointer[map[string]*hotCandidate[K]]
+}
+
+type hotCandidate[K comparable] struct {
+	key    K
+	keyStr string
+	count  atomic.Uint32
+}
+
+// raise sets the count to n when n is higher and reports whether it did.
+func (c *hotCandidate[K]) raise(n uint32) bool {
+	for {
+		old := c.count.Load()
+		if n <= old {
+			return false
+		}
+		if c.count.CompareAndSwap(old, n) {
+			return true
+		}
+	}
+}
+
+func newHotKeyTracker[K comparable](cfg HotKeyConfig) *hotKeyTracker[K] {
+	width := uint64(1) << bits.Len64(uint64(cfg.Width-1))
+	t := &hotKeyTracker[K]{
+		mask:     width - 1,
+		topK:     cfg.TopK,
+		minCount: cfg.MinCount,
+	}
+	for i := range t.rows {
+		t.rows[i] = make([]atomic.Uint32, width)
+	}
+	t.candidates.Store(&map[string]*hotCandidate[K]{})
+	return t
+}
+
+// record counts one read of key and returns its estimated frequency.
+func (t *hotKeyTracker[K]) record(key K, keyStr string) uint32 {
+	h1, h2 := z.KeyToHash(keyStr)
+	est := ^uint32(0)
+	for i := range t.rows {
+		// Kirsch–Mitzenmacher: derive the row hashes from two base hashes.
+		n := t.rows[i][(h1+uint64(i)*h2)&t.mask].Add(1)
+		est = min(est, n)
+	}
+	if est <= t.floor.Load() {
+		return est
+	}
+
+	if c, ok := (*t.candidates.Load())[keyStr]; ok {
+		// Raising any candidate but the coldest leaves the floor as is.
+		if c.raise(est) && c == t.coldest.Load() {
+			t.mu.Lock()
+			t.updateFloor(*t.candidates.Load())
+			t.mu.Unlock()
+		}
+		return est
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cur := *t.candidates.Load()
+	if c, ok := cur[keyStr]; ok { // added concurrently
+		c.raise(est)
+		t.updateFloor(cur)
+		return est
+	}
+	next := make(map[string]*hotCandidate[K], t.topK)
+	for k, c := range cur {
+		next[k] = c
+	}
+	if len(cur) >= t.topK {
+		coldest := t.coldest.Load()
+		if coldest == nil || est <= coldest.count.Load() {
+			return est
+		}
+		delete(next, coldest.keyStr)
+	}
+	c := &hotCandidate[K]{key: key, keyStr: keyStr}
+	c.count.Store(est)
+	next[keyStr] = c
+	t.candidates.Store(&next)
+	t.updateFloor(next)
+	return est
+}
+
+// updateFloor sets floor and coldest from the candidates m once TopK are
+// tracked, and clears them otherwise. Caller holds mu.
+func (t *hotKeyTracker[K]) updateFloor(m map[string]*hotCandidate[K]) {
+	if len(m) < t.topK {
+		t.coldest.Store(nil)
+		t.floor.Store(0)
+		return
+	}
+	var (
+		coldest *hotCandidate[K]
+		floor   = ^uint32(0)
+	)
+	for _, c := range m {
+		if n := c.count.Load(); n < floor {
+			coldest, floor = c, n
+		}
+	}
+	t.coldest.Store(coldest)
+	t.floor.Store(floor)
+}
+
+// isHot reports whether keyStr is a top key above MinCount.
+func (t *hotKeyTracker[K]) isHot(keyStr string) bool {
+	c, ok := (*t.candidates.Load())[keyStr]
+	return ok && c.count.Load() >= t.minCount
+}
+
+// top returns the hot keys, hottest first.
+func (t *hotKeyTracker[K]) top() []HotKey {
+	cur := *t.candidates.Load()
+	out := make([]HotKey, 0, len(cur))
+	for k, c := range cur {
+		if n := c.count.Load(); n >= t.minCount {
+			out = append(out, HotKey{Key: k, Count: n})
+		}
+	}
+	slices.SortFunc(out, func(a, b HotKey) int { return cmp.Compare(b.Count, a.Count) })
+	return out
+}
+
+// hotKeyList returns the original keys of the hot candidates.
+func (t *hotKeyTracker[K]) hotKeyList() []K {
+	cur := *t.candidates.Load()
+	out := make([]K, 0, len(cur))
+	for _, c := range cur {
+		if c.count.Load() >= t.minCount {
+			out = append(out, c.key)
+		}
+	}
+	return out
+}
+
+// decay halves every counter, so frequencies reflect recent windows.
+// Candidates that decay to zero are dropped, and the floor is recomputed
+// from the halved counts so record stays on its fast path.
+func (t *hotKeyTracker[K]) decay() {
+	for i := range t.rows {
+		for j := range t.rows[i] {
+			halve(&t.rows[i][j])
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cur := *t.candidates.Load()
+	next := make(map[string]*hotCandidate[K], t.topK)
+	for k, c := range cur {
+		if halve(&c.count) > 0 {
+			next[k] = c
+		}
+	}
+	t.candidates.Store(&next)
+	t.updateFloor(next)
+}
+
+// halve divides n by two and returns the new value.
+func halve(n *atomic.Uint32) uint32 {
+	for {
+		old := n.Load()
+		if old == 0 || n.CompareAndSwap(old, old/2) {
+			return old / 2
+		}
+	}
+}
+
+// hotKeys runs hot-key detection and promotion for a multiLevel cache.
+type hotKeys[K comparable, V any] struct {
+	tracker    *hotKeyTracker[K]
+	keyFn      KeyFunc[K]
+	promoteTTL time.Duration
+	l1, l2     Cache[K, V]
+
+	stats HotKeyStats // nil when Stats does not implement HotKeyStats
+	name  string
+
+	closeChan chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func newHotKeys[K comparable, V any](
+	cfg HotKeyConfig,
+	l1, l2 Cache[K, V],
+	keyFn KeyFunc[K],
+	stats Stats,
+	name string,
+) *hotKeys[K, V] {
+	if cfg.TopK <= 0 {
+		cfg.TopK = defaultHotKeyTopK
+	}
+	if cfg.Width <= 0 {
+		cfg.Width = defaultHotKeyWidth
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultHotKeyWindow
+	}
+	if cfg.MinCount == 0 {
+		cfg.MinCount = defaultHotKeyMinCount
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = defaultHotKeyRefreshInterval
+	}
+
+	h := &hotKeys[K, V]{
+		tracker:    newHotKeyTracker[K](cfg),
+		keyFn:      keyFn,
+		promoteTTL: cfg.PromoteTTL,
+		l1:         l1,
+		l2:         l2,
+		name:       name,
+		closeChan:  make(chan struct{}),
+	}
+	h.stats, _ = stats.(HotKeyStats)
+
+	h.wg.Add(1)
+	go h.windowLoop(cfg.Window)
+	if h.promoteTTL > 0 {
+		h.wg.Add(1)
+		go h.refreshLoop(cfg.RefreshInterval)
+	}
+	return h
+}
+
+func (h *hotKeys[K, V]) record(key K) {
+	h.tracker.record(key, h.keyFn(key))
+}
+
+// l1TTL returns the L1 TTL for a key read from L2: PromoteTTL for hot keys,
+// zero (the L1 default) otherwise.
+func (h *hotKeys[K, V]) l1TTL(key K) time.Duration {
+	if h.promoteTTL > 0 && h.tracker.isHot(h.keyFn(key)) {
+		return h.promoteTTL
+	}
+	return 0
+}
+
+func (h *hotKeys[K, V]) top() []HotKey { return h.tracker.top() }
+
+func (h *hotKeys[K, V]) windowLoop(window time.Duration) {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if h.stats != nil {
+				h.stats.HotKeys(context.Background(), h.name, h.tracker.top())
+			}
+			h.tracker.decay()
+		case <-h.closeChan:
+			return
+		}
+	}
+}
+
+// refreshLoop re-reads promoted keys from L2, bounding the staleness of their
+// extended L1 copies to RefreshInterval.
+func (h *hotKeys[K, V]) refreshLoop(interval time.Duration) {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.refresh()
+		case <-h.closeChan:
+			return
+		}
+	}
+}
+
+func (h *hotKeys[K, V]) refresh() {
+	keys := h.tracker.hotKeyList()
+	if len(keys) == 0 {
+		return
+	}
+	ctx := context.Background()
+	fresh, err := h.l2.GetMany(ctx, keys)
+	if err != nil {
+		return // keep the current L1 copies until the next refresh
+	}
+	for _, k := range keys {
+		if v, ok := fresh[k]; ok {
+			_ = h.l1.SetTTL(ctx, k, v, h.promoteTTL)
+		} else {
+			_ = h.l1.Delete(ctx, k)
+		}
+	}
+}
+
+func (h *hotKeys[K, V]) Close() {
+	h.closeOnce.Do(func() {
+		close(h.closeChan)
+		h.wg.Wait()
+	})
+}
@@ -0,0 +1,165 @@
+package cache
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func newTestTracker(topK int, minCount uint32) *hotKeyTracker[string] {
+	return newHotKeyTracker[string](HotKeyConfig{TopK: topK, Width: 1024, MinCount: minCount})
+}
+
+func recordN(t *hotKeyTracker[string], key string, n int) {
+	for range n {
+		t.record(key, key)
+	}
+}
+
+func TestHotKeyTrackerTop(t *testing.T) {
+	tests := []struct {
+		name     string
+		topK     int
+		minCount uint32
+		reads    map[string]int
+		want     []string // hottest first
+	}{
+		{
+			name:  "fewer keys than TopK",
+			topK:  4,
+			reads: map[string]int{"a": 3, "b": 1},
+			want:  []string{"a", "b"},
+		},
+		{
+			name:  "keeps the hottest",
+			topK:  3,
+			reads: map[string]int{"a": 50, "b": 10, "c": 40, "d": 20, "e": 30},
+			want:  []string{"a", "c", "e"},
+		},
+		{
+			name:     "below MinCount",
+			topK:     3,
+			minCount: 25,
+			reads:    map[string]int{"a": 50, "b": 10, "c": 40, "d": 20},
+			want:     []string{"a", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTestTracker(tt.topK, tt.minCount)
+			// Interleave the reads, so keys enter the top in changing order.
+			for remaining := true; remaining; {
+				remaining = false
+				for _, k := range slices.Sorted(maps.Keys(tt.reads)) {
+					if tt.reads[k] > 0 {
+						tr.record(k, k)
+						tt.reads[k]--
+						remaining = true
+					}
+				}
+			}
+
+			var got []string
+			for _, hk := range tr.top() {
+				got = append(got, hk.Key)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("top = %v, want %v", got, tt.want)
+			}
+			for _, k := range tt.want {
+				if !tr.isHot(k) {
+					t.Errorf("isHot(%q) = false, want true", k)
+				}
+			}
+		})
+	}
+}
+
+func TestHotKeyTrackerNeverUnderestimates(t *testing.T) {
+	tr := newTestTracker(8, 1)
+	for i := range 500 {
+		recordN(tr, fmt.Sprint("noise", i), 1+i%5)
+	}
+	recordN(tr, "hot", 200)
+	if est := tr.record("hot", "hot"); est < 201 {
+		t.Errorf("estimate %d is below the 201 reads", est)
+	}
+	if top := tr.top(); len(top) == 0 || top[0].Key != "hot" {
+		t.Errorf("top = %v, want hot first", top)
+	}
+}
+
+func TestHotKeyTrackerDecay(t *testing.T) {
+	tr := newTestTracker(2, 1)
+	recordN(tr, "a", 8)
+	recordN(tr, "b", 4)
+	recordN(tr, "c", 1) // below the floor of 4, not tracked
+	if floor := tr.floor.Load(); floor != 4 {
+		t.Fatalf("floor = %d, want 4", floor)
+	}
+
+	tr.decay()
+	if got := tr.top(); len(got) != 2 || got[0].Count != 4 || got[1].Count != 2 {
+		t.Fatalf("after decay: top = %v, want counts 4 and 2", got)
+	}
+	// The floor follows the halved counts instead of dropping to zero, so
+	// reads of cold keys stay on the lock-free path.
+	if floor := tr.floor.Load(); floor != 2 {
+		t.Errorf("after decay: floor = %d, want 2", floor)
+	}
+
+	tr.decay()
+	tr.decay()
+	if got := tr.top(); len(got) != 1 || got[0].Key != "a" {
+		t.Errorf("after decaying b to zero: top = %v, want only a", got)
+	}
+	if floor := tr.floor.Load(); floor != 0 {
+		t.Errorf("with a free slot: floor = %d, want 0", floor)
+	}
+}
+
+func TestHotKeyTrackerColdestRaised(t *testing.T) {
+	tr := newTestTracker(2, 1)
+	recordN(tr, "a", 5)
+	recordN(tr, "b", 3)
+	if floor := tr.floor.Load(); floor != 3 {
+		t.Fatalf("floor = %d, want 3", floor)
+	}
+	recordN(tr, "b", 4) // b overtakes a, a becomes the coldest
+	if floor := tr.floor.Load(); floor != 5 {
+		t.Errorf("floor = %d, want 5 after the coldest was raised", floor)
+	}
+	if c := tr.coldest.Load(); c == nil || c.keyStr != "a" {
+		t.Errorf("coldest = %v, want a", c)
+	}
+}
+
+func TestHotKeyTrackerConcurrentRecord(t *testing.T) {
+	tr := newTestTracker(4, 1)
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				key := fmt.Sprint("k", (g+i)%6)
+				tr.record(key, key)
+			}
+		}()
+	}
+	wg.Wait()
+
+	top := tr.top()
+	if len(top) != 4 {
+		t.Fatalf("top = %v, want 4 keys", top)
+	}
+	var total uint32
+	for _, hk := range top {
+		total += hk.Count
+	}
+	if total < 4*8000/6 {
+		t.Errorf("top counts %v add up to %d, want at least %d", top, total, 4*8000/6)
+	}
+}
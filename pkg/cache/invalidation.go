@@ -0,0 +1,298 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// InvalidationConfig configures cross-instance L1 invalidation over Redis pub/sub.
+//
+// Every Set/SetTTL/Delete on a multilevel cache publishes the affected key to
+// a channel derived from the cache Name. Every process subscribed to the same
+// channel evicts that key from its local L1, so replicas observe writes made
+// elsewhere without waiting for the L1 TTL to run out.
+type InvalidationConfig struct {
+	// Client is the Redis client used for PUBLISH and SUBSCRIBE.
+	// Defaults to RedisConfig.Client of the L2 layer.
+	// Any redis.UniversalClient works; on Cluster, PUBLISH is broadcast to all nodes.
+	Client redis.UniversalClient
+	// Channel is the pub/sub channel name.
+	// Defaults to "cache:invalidate:{name}", falling back to the L2 Prefix when Name is empty.
+	Channel string
+	// BatchSize is the maximum number of keys sent in one message. Default: 100.
+	BatchSize int
+	// FlushInterval is the maximum time a key waits before its batch is published.
+	// Default: 10ms.
+	FlushInterval time.Duration
+	// MaxPending bounds the number of keys buffered while Redis is unreachable.
+	// Keys beyond the limit are dropped; remote replicas then rely on L1 TTL.
+	// Default: 100 × BatchSize.
+	MaxPending int
+}
+
+const (
+	defaultInvalidationBatchSize     = 100
+	defaultInvalidationFlushInterval = 10 * time.Millisecond
+	invalidationChannelPrefix        = "cache:invalidate:"
+)
+
+// invalidationMessage is the wire format published on the invalidation channel.
+// Keys are already serialized with the cache KeyFunc. All asks receivers to
+// clear their whole L1 (sent by Flush).
+type invalidationMessage struct {
+	Origin string   `json:"o"`
+	Keys   []string `json:"k,omitempty"`
+	All    bool     `json:"a,omitempty"`
+}
+
+// invalidationBus publishes local writes and applies remote invalidations to L1.
+//
+// Publishing is batched: keys are buffered and flushed when BatchSize is reached
+// or FlushInterval elapses. Messages carry a per-process origin ID so a process
+// ignores its own invalidations (its L1 was already updated by the write).
+//
+// go-redis re-subscribes transparently after a connection loss, but messages
+// published in between are lost. Every re-subscription therefore clears the
+// whole L1 — a cold L1 is preferable to a stale one.
+type invalidationBus[K comparable, V any] struct {
+	client  redis.UniversalClient
+	channel string
+	origin  string
+	l1      *ristrettoCache[K, V]
+	keyFn   KeyFunc[K]
+
+	batchSize     int
+	flushInterval time.Duration
+	maxPending    int
+
+	stats Stats
+	name  string
+
+	mu      sync.Mutex
+	pending []string
+
+	pubsub    *redis.PubSub
+	flushChan chan struct{}
+	closeChan chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func newInvalidationBus[K comparable, V any](
+	cfg InvalidationConfig,
+	l1 *ristrettoCache[K, V],
+	keyFn KeyFunc[K],
+	stats Stats,
+	name string,
+) (*invalidationBus[K, V], error) {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultInvalidationBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultInvalidationFlushInterval
+	}
+	if cfg.MaxPending <= 0 {
+		cfg.MaxPending = 100 * cfg.BatchSize
+	}
+
+	origin, err := newOriginID()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &invalidationBus[K, V]{
+		client:        cfg.Client,
+		channel:       cfg.Channel,
+		origin:        origin,
+		l1:            l1,
+		keyFn:         keyFn,
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		maxPending:    cfg.MaxPending,
+		stats:         stats,
+		name:          name,
+		flushChan:     make(chan struct{}, 1),
+		closeChan:     make(chan struct{}),
+	}
+
+	b.pubsub = b.client.Subscribe(context.Background(), b.channel)
+
+	b.wg.Add(2)
+	go b.publishLoop()
+	go b.subscribeLoop()
+
+	return b, nil
+}
+
+func newOriginID() (string, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
+
+// Publish schedules key for invalidation on all other subscribed processes.
+// Never blocks on Redis; delivery is best-effort.
+func (b *invalidationBus[K, V]) Publish(key K) {
+	b.publishKeys(b.keyFn(key))
+}
+
+// publishKeys is Publish for keys already serialized with keyFn.
+func (b *invalidationBus[K, V]) publishKeys(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	room := b.maxPending - len(b.pending)
+	if len(keys) > room {
+		keys = keys[:max(room, 0)]
+	}
+	b.pending = append(b.pending, keys...)
+	full := len(b.pending) >= b.batchSize
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.flushChan <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *invalidationBus[K, V]) publishLoop() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.flush()
+		case <-b.flushChan:
+			b.flush()
+		case <-b.closeChan:
+			b.flush()
+			return
+		}
+	}
+}
+
+func (b *invalidationBus[K, V]) flush() {
+	b.mu.Lock()
+	keys := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	for len(keys) > 0 {
+		n := min(len(keys), b.batchSize)
+		batch := keys[:n]
+
+		if err := b.send(batch); err != nil {
+			b.reportError()
+			// Keep the unsent keys for the next flush; Redis may be reconnecting.
+			b.requeue(keys)
+			return
+		}
+		keys = keys[n:]
+	}
+}
+
+// publishFlush asks every other process to clear its L1. Sent immediately,
+// outside the key batches; on failure remote L1s rely on their TTL.
+func (b *invalidationBus[K, V]) publishFlush() {
+	if err := b.publish(invalidationMessage{Origin: b.origin, All: true}); err != nil {
+		b.reportError()
+	}
+}
+
+func (b *invalidationBus[K, V]) send(keys []string) error {
+	return b.publish(invalidationMessage{Origin: b.origin, Keys: keys})
+}
+
+func (b *invalidationBus[K, V]) publish(msg invalidationMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+func (b *invalidationBus[K, V]) requeue(keys []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	room := b.maxPending - len(b.pending)
+	if room <= 0 {
+		return
+	}
+	if len(keys) > room {
+		keys = keys[:room]
+	}
+	b.pending = append(keys, b.pending...)
+}
+
+func (b *invalidationBus[K, V]) subscribeLoop() {
+	defer b.wg.Done()
+
+	subscribed := false
+	for msg := range b.pubsub.ChannelWithSubscriptions() {
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind != "subscribe" {
+				continue
+			}
+			if subscribed {
+				// Re-subscribed after a reconnect — invalidations may have been missed.
+				b.l1.clear()
+			}
+			subscribed = true
+		case *redis.Message:
+			b.apply(m.Payload)
+		}
+	}
+}
+
+func (b *invalidationBus[K, V]) apply(payload string) {
+	var msg invalidationMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		b.reportError()
+		return
+	}
+	if msg.Origin == b.origin {
+		return
+	}
+	if msg.All {
+		b.l1.clear()
+		return
+	}
+	for _, k := range msg.Keys {
+		b.l1.delString(k)
+	}
+}
+
+func (b *invalidationBus[K, V]) reportError() {
+	if b.stats != nil {
+		b.stats.Error(context.Background(), b.name, "l1", "invalidate")
+	}
+}
+
+// Close flushes pending invalidations and stops the subscriber.
+func (b *invalidationBus[K, V]) Close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		close(b.closeChan)
+		err = b.pubsub.Close()
+		b.wg.Wait()
+	})
+	return err
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrUnknownKey is returned by a Keyring when no key has the requested ID.
+var ErrUnknownKey = errors.New("cache: unknown encryption key")
+
+// Keyring supplies AES keys to the Encrypted codec.
+//
+// Key IDs are stored in every payload and must never be reused for different
+// key material. To rotate, add a new key, make it current, and keep the old
+// one until every entry written with it has expired (the L2 TTL).
+type Keyring interface {
+	// Current returns the ID and key used to encrypt new payloads.
+	Current() (id string, key []byte, err error)
+	// Key returns the key with the given ID, or an error wrapping ErrUnknownKey.
+	Key(id string) ([]byte, error)
+}
+
+// StaticKeyring returns a Keyring over a fixed set of keys.
+// current must be one of keys. Keys must be 16, 24 or 32 bytes long
+// (AES-128, AES-192 or AES-256).
+func StaticKeyring(current string, keys map[string][]byte) (Keyring, error) {
+	set, err := newKeySet(current, keys)
+	if err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// keySet is an immutable, validated set of keys.
+type keySet struct {
+	current string
+	keys    map[string][]byte
+}
+
+func newKeySet(current string, keys map[string][]byte) (*keySet, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("cache: current key %q is not in the keyring", current)
+	}
+	set := &keySet{current: current, keys: make(map[string][]byte, len(keys))}
+	for id, key := range keys {
+		if id == "" || len(id) > maxKeyIDLen {
+			return nil, fmt.Errorf("cache: key ID %q must be 1-%d bytes long", id, maxKeyIDLen)
+		}
+		switch len(key) {
+		case 16, 24, 32:
+		default:
+			return nil, fmt.Errorf("cache: key %q must be 16, 24 or 32 bytes long, got %d", id, len(key))
+		}
+		set.keys[id] = key
+	}
+	return set, nil
+}
+
+func (s *keySet) Current() (string, []byte, error) {
+	return s.current, s.keys[s.current], nil
+}
+
+func (s *keySet) Key(id string) ([]byte, error) {
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+	return key, nil
+}
+
+// FileKeyring is a Keyring loaded from a JSON file:
+//
+//	{
+//	    "current": "2026-10",
+//	    "keys": {
+//	        "2026-07": "base64-encoded 32 bytes",
+//	        "2026-10": "base64-encoded 32 bytes"
+//	    }
+//	}
+//
+// The file is typically a mounted Kubernetes or Vault secret. When a payload
+// references an unknown key ID — another replica already picked up a rotated
+// file — the file is re-read if it has changed, at most once per second.
+// Call Reload to pick up a new current key, e.g. on SIGHUP.
+type FileKeyring struct {
+	path string
+
+	mu      sync.RWMutex
+	set     *keySet
+	modTime time.Time
+	checked time.Time
+}
+
+// fileKeyringRecheck bounds how often an unknown key ID triggers a file stat.
+const fileKeyringRecheck = time.Second
+
+type fileKeyringJSON struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// NewFileKeyring loads the keyring file at path.
+func NewFileKeyring(path string) (*FileKeyring, error) {
+	k := &FileKeyring{path: path}
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload re-reads the keyring file. On error the previously loaded keys stay in use.
+func (k *FileKeyring) Reload() error {
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return fmt.Errorf("cache: keyring: %w", err)
+	}
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		return fmt.Errorf("cache: keyring: %w", err)
+	}
+
+	var file fileKeyringJSON
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("cache: keyring %s: %w", k.path, err)
+	}
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, enc := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(enc)
+		if err != nil {
+			return fmt.Errorf("cache: keyring %s: key %q: %w", k.path, id, err)
+		}
+		keys[id] = key
+	}
+	set, err := newKeySet(file.Current, keys)
+	if err != nil {
+		return fmt.Errorf("cache: keyring %s: %w", k.path, err)
+	}
+
+	k.mu.Lock()
+	k.set = set
+	k.modTime = info.ModTime()
+	k.checked = time.Now()
+	k.mu.Unlock()
+	return nil
+}
+
+func (k *FileKeyring) Current() (string, []byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.set.Current()
+}
+
+func (k *FileKeyring) Key(id string) ([]byte, error) {
+	k.mu.RLock()
+	key, err := k.set.Key(id)
+	k.mu.RUnlock()
+	if err == nil || !k.reloadIfChanged() {
+		return key, err
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.set.Key(id)
+}
+
+// reloadIfChanged reloads the file when its modification time has changed.
+// Reports whether the keys were reloaded.
+func (k *FileKeyring) reloadIfChanged() bool {
+	k.mu.Lock()
+	if time.Since(k.checked) < fileKeyringRecheck {
+		k.mu.Unlock()
+		return false
+	}
+	k.checked = time.Now()
+	modTime := k.modTime
+	k.mu.Unlock()
+
+	info, err := os.Stat(k.path)
+	if err != nil || info.ModTime().Equal(modTime) {
+		return false
+	}
+	return k.Reload() == nil
+}
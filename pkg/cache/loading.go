@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"math/rand/v2"
 	"sync"
 	"time"
 
@@ -18,19 +20,48 @@ var ErrNotFound = errors.New("cache: not found")
 // Return (zero, ErrNotFound) to enable negative caching for this key.
 type LoadFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
 
+// BatchLoadFunc loads many values from the authoritative source in one call.
+// Keys absent from the returned map are treated like ErrNotFound and are
+// negative-cached when LoadingConfig.NegativeTTL is set.
+type BatchLoadFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
 // LoadingConfig configures the loading and refresh behaviour.
 type LoadingConfig struct {
 	// SoftTTL is the age after which a cached entry is considered stale.
 	// On Get, stale entries are returned immediately while a background
 	// refresh is triggered (stale-while-revalidate). Must be shorter than
 	// the L1/L2 TTL so the hard expiry evicts entries that were never refreshed.
+	// Age is measured from the local load, or from the write time stored in
+	// L2 when the L2 codec is wrapped with Envelope.
 	// Zero disables stale-while-revalidate.
 	SoftTTL time.Duration
 
+	// EarlyRefreshBeta enables probabilistic early refresh (XFetch): a Get may
+	// trigger the background refresh before SoftTTL elapses, with a probability
+	// that grows as the soft expiry approaches and with how long the previous
+	// load took. Spreads refreshes of a hot key instead of every replica
+	// refreshing it at the same moment. 1.0 is the usual value; larger values
+	// refresh earlier. Zero disables. Requires SoftTTL.
+	EarlyRefreshBeta float64
+
 	// NegativeTTL is how long to cache a LoadFunc ErrNotFound result.
 	// Prevents thundering-herd on non-existent keys.
 	// Zero disables negative caching.
 	NegativeTTL time.Duration
+
+	// BatchWait is how long the batch loader collects misses before calling
+	// BatchLoadFunc. Only used with Builder.WithBatchLoader. Default: 2ms.
+	BatchWait time.Duration
+
+	// MaxBatchSize dispatches a batch early once it holds this many keys.
+	// Only used with Builder.WithBatchLoader. Default: 100.
+	MaxBatchSize int
+}
+
+// softEntry is the stale-while-revalidate state of one key.
+type softEntry struct {
+	expiry time.Time
+	delta  time.Duration // duration of the load that produced the value
 }
 
 // sfValue wraps the generic result for singleflight so nil-interface values
@@ -41,16 +72,22 @@ type sfValue[V any] struct{ v V }
 // Concurrent loads for the same key are deduplicated via singleflight —
 // one goroutine calls LoadFunc; all others wait and share the result.
 // Individual callers can cancel their context without aborting the shared load.
+//
+// When a BatchLoadFunc is configured, misses from Get and GetMany are routed
+// through a batchLoader instead, which both deduplicates and coalesces them.
 type loadingCache[K comparable, V any] struct {
 	cache   Cache[K, V]
 	loader  LoadFunc[K, V]
+	batch   *batchLoader[K, V] // nil unless Builder.WithBatchLoader is set
 	group   singleflight.Group
 	keyFn   KeyFunc[K]
 	softTTL time.Duration
+	beta    float64
 
 	// softExpiry tracks per-key stale-while-revalidate deadlines.
-	// Process-local; reset on restart is acceptable — one extra loader call.
-	softExpiry sync.Map // string → time.Time
+	// Seeded by local loads and writes, and by L2 reads when the codec is an
+	// Envelope. Without Envelope it is process-local and reset on restart.
+	softExpiry sync.Map // string → softEntry
 
 	// refreshing guards against duplicate background refreshes per key.
 	refreshing sync.Map // string → struct{}
@@ -63,6 +100,7 @@ type loadingCache[K comparable, V any] struct {
 func newLoadingCache[K comparable, V any](
 	c Cache[K, V],
 	loader LoadFunc[K, V],
+	batchFn BatchLoadFunc[K, V],
 	keyFn KeyFunc[K],
 	cfg LoadingConfig,
 ) Cache[K, V] {
@@ -71,6 +109,7 @@ func newLoadingCache[K comparable, V any](
 		loader:  loader,
 		keyFn:   keyFn,
 		softTTL: cfg.SoftTTL,
+		beta:    cfg.EarlyRefreshBeta,
 	}
 	if cfg.NegativeTTL > 0 {
 		// Negative cache is always local — no need to replicate to Redis.
@@ -83,6 +122,9 @@ func newLoadingCache[K comparable, V any](
 			lc.negative = neg
 		}
 	}
+	if batchFn != nil {
+		lc.batch = newBatchLoader(batchFn, keyFn, cfg, lc.storeBatch)
+	}
 	return lc
 }
 
@@ -95,22 +137,21 @@ func (c *loadingCache[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
 		}
 	}
 
-	v, ok, err := c.cache.Get(ctx, key)
+	readCtx, sink := ctx, (*metaSink)(nil)
+	if c.softTTL > 0 {
+		readCtx, sink = withMetaSink(ctx)
+	}
+
+	v, ok, err := c.cache.Get(readCtx, key)
 	if err == nil && ok {
 		if c.softTTL > 0 {
-			keyStr := c.keyFn(key)
-			if exp, loaded := c.softExpiry.Load(keyStr); loaded {
-				if time.Now().After(exp.(time.Time)) {
-					// Stale — return immediately, refresh in background.
-					c.triggerBackgroundRefresh(key, keyStr)
-				}
-			}
+			c.checkStale(key, c.keyFn(key), sink, time.Now())
 		}
 		return v, true, nil
 	}
 
 	// Cache miss (or read error) — load from source, deduplicated.
-	loaded, err := c.doLoad(ctx, key)
+	loaded, err := c.load(ctx, key)
 	if err != nil {
 		var zero V
 		return zero, false, err
@@ -118,6 +159,13 @@ func (c *loadingCache[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
 	return loaded, true, nil
 }
 
+func (c *loadingCache[K, V]) load(ctx context.Context, key K) (V, error) {
+	if c.batch != nil {
+		return c.batch.load(ctx, key)
+	}
+	return c.doLoad(ctx, key)
+}
+
 func (c *loadingCache[K, V]) doLoad(ctx context.Context, key K) (V, error) {
 	keyStr := c.keyFn(key)
 
@@ -125,7 +173,9 @@ func (c *loadingCache[K, V]) doLoad(ctx context.Context, key K) (V, error) {
 	ch := c.group.DoChan(keyStr, func() (any, error) {
 		// Detach from caller context: one cancellation must not abort the
 		// load that other waiting goroutines are relying on.
+		start := time.Now()
 		v, err := c.loader(context.WithoutCancel(ctx), key)
+		took := time.Since(start)
 
 		if errors.Is(err, ErrNotFound) {
 			if c.negative != nil {
@@ -137,10 +187,13 @@ func (c *loadingCache[K, V]) doLoad(ctx context.Context, key K) (V, error) {
 			return nil, err
 		}
 
-		_ = c.cache.Set(context.Background(), key, v)
-		if c.softTTL > 0 {
-			c.softExpiry.Store(keyStr, time.Now().Add(c.softTTL))
+		setCtx := withLoadDelta(context.Background(), took)
+		if ttl := loadTTLFrom(ctx); ttl > 0 {
+			_ = c.cache.SetTTL(setCtx, key, v, ttl)
+		} else {
+			_ = c.cache.Set(setCtx, key, v)
 		}
+		c.markFresh(keyStr, time.Now(), took)
 		return sfValue[V]{v: v}, nil
 	})
 
@@ -164,19 +217,171 @@ func (c *loadingCache[K, V]) triggerBackgroundRefresh(key K, keyStr string) {
 	go func() {
 		defer c.refreshing.Delete(keyStr)
 
+		if c.batch != nil {
+			// The batch loader stores the result itself.
+			_, _ = c.batch.load(context.Background(), key)
+			return
+		}
+
+		start := time.Now()
 		v, err := c.loader(context.Background(), key)
 		if err != nil {
 			return
 		}
-		_ = c.cache.Set(context.Background(), key, v)
-		c.softExpiry.Store(keyStr, time.Now().Add(c.softTTL))
+		took := time.Since(start)
+		_ = c.cache.Set(withLoadDelta(context.Background(), took), key, v)
+		c.markFresh(keyStr, time.Now(), took)
 	}()
 }
 
-func (c *loadingCache[K, V]) Set(ctx context.Context, key K, value V) error {
+// markFresh restarts the soft TTL of keyStr from writtenAt.
+func (c *loadingCache[K, V]) markFresh(keyStr string, writtenAt time.Time, took time.Duration) {
 	if c.softTTL > 0 {
-		c.softExpiry.Store(c.keyFn(key), time.Now().Add(c.softTTL))
+		c.softExpiry.Store(keyStr, softEntry{expiry: writtenAt.Add(c.softTTL), delta: took})
+	}
+}
+
+// checkStale triggers a background refresh when the entry is past its soft
+// expiry — or, with EarlyRefreshBeta, probabilistically shortly before it.
+// Metadata recorded in sink (the value came from an enveloped L2 payload)
+// takes precedence over the locally known state.
+func (c *loadingCache[K, V]) checkStale(key K, keyStr string, sink *metaSink, now time.Time) {
+	if sink != nil {
+		if meta, ok := sink.get(keyStr); ok {
+			c.markFresh(keyStr, meta.WrittenAt, meta.Delta)
+		}
+	}
+	e, ok := c.softExpiry.Load(keyStr)
+	if !ok {
+		return
+	}
+	entry := e.(softEntry)
+	if xfetchExpired(now, entry.expiry, entry.delta, c.beta) {
+		// Stale — return immediately, refresh in background.
+		c.triggerBackgroundRefresh(key, keyStr)
+	}
+}
+
+// xfetchExpired reports whether an entry expiring at expiry should be
+// refreshed now. With beta > 0 it implements XFetch (Vattani et al., 2015):
+// refresh once now − delta·beta·ln(rand) ≥ expiry, so keys that are costly
+// to load are refreshed earlier and concurrent readers rarely agree on when.
+func xfetchExpired(now, expiry time.Time, delta time.Duration, beta float64) bool {
+	if beta > 0 && delta > 0 {
+		// 1 − Float64 is in (0, 1], keeping the logarithm finite.
+		gap := -float64(delta) * beta * math.Log(1-rand.Float64())
+		now = now.Add(time.Duration(gap))
+	}
+	return !now.Before(expiry)
+}
+
+// storeBatch persists a batch load: found values go to the cache with a fresh
+// soft expiry, absent keys go to the negative cache.
+func (c *loadingCache[K, V]) storeBatch(loaded map[K]V, absent []K, took time.Duration) {
+	ctx := context.Background()
+	if len(loaded) > 0 {
+		_ = c.cache.SetMany(withLoadDelta(ctx, took), loaded)
+		now := time.Now()
+		for k := range loaded {
+			c.markFresh(c.keyFn(k), now, took)
+		}
 	}
+	if c.negative != nil {
+		for _, k := range absent {
+			_ = c.negative.Set(ctx, k, struct{}{})
+		}
+	}
+}
+
+// GetMany reads all keys from the cache and loads the misses. Known-absent
+// keys (negative cache or ErrNotFound from the loader) are omitted from the
+// result without an error.
+func (c *loadingCache[K, V]) GetMany(ctx context.Context, keys []K) (map[K]V, error) {
+	lookup := keys
+	if c.negative != nil {
+		if neg, _ := c.negative.GetMany(ctx, keys); len(neg) > 0 {
+			lookup = make([]K, 0, len(keys))
+			for _, k := range keys {
+				if _, ok := neg[k]; !ok {
+					lookup = append(lookup, k)
+				}
+			}
+		}
+	}
+
+	readCtx, sink := ctx, (*metaSink)(nil)
+	if c.softTTL > 0 {
+		readCtx, sink = withMetaSink(ctx)
+	}
+
+	// Read errors are treated as misses, as in Get.
+	found, _ := c.cache.GetMany(readCtx, lookup)
+	if found == nil {
+		found = make(map[K]V, len(lookup))
+	}
+
+	if c.softTTL > 0 {
+		now := time.Now()
+		for k := range found {
+			c.checkStale(k, c.keyFn(k), sink, now)
+		}
+	}
+
+	missed := make([]K, 0, len(lookup)-len(found))
+	for _, k := range lookup {
+		if _, ok := found[k]; !ok {
+			missed = append(missed, k)
+		}
+	}
+	if len(missed) == 0 {
+		return found, nil
+	}
+
+	loaded, err := c.loadMany(ctx, missed)
+	for k, v := range loaded {
+		found[k] = v
+	}
+	return found, err
+}
+
+func (c *loadingCache[K, V]) loadMany(ctx context.Context, keys []K) (map[K]V, error) {
+	if c.batch != nil {
+		return c.batch.loadMany(ctx, keys)
+	}
+
+	type result struct {
+		key K
+		val V
+		err error
+	}
+
+	results := make(chan result, len(keys))
+	for _, key := range keys {
+		go func() {
+			v, err := c.doLoad(ctx, key)
+			results <- result{key: key, val: v, err: err}
+		}()
+	}
+
+	var (
+		loaded = make(map[K]V, len(keys))
+		first  error
+	)
+	for range keys {
+		r := <-results
+		switch {
+		case r.err == nil:
+			loaded[r.key] = r.val
+		case errors.Is(r.err, ErrNotFound):
+		case first == nil:
+			first = r.err
+		}
+	}
+	return loaded, first
+}
+
+func (c *loadingCache[K, V]) Set(ctx context.Context, key K, value V) error {
+	c.markFresh(c.keyFn(key), time.Now(), 0)
 	if c.negative != nil {
 		_ = c.negative.Delete(ctx, key)
 	}
@@ -197,6 +402,47 @@ func (c *loadingCache[K, V]) Delete(ctx context.Context, key K) error {
 	return c.cache.Delete(ctx, key)
 }
 
+func (c *loadingCache[K, V]) SetMany(ctx context.Context, entries map[K]V) error {
+	now := time.Now()
+	for k := range entries {
+		c.markFresh(c.keyFn(k), now, 0)
+	}
+	if c.negative != nil {
+		for k := range entries {
+			_ = c.negative.Delete(ctx, k)
+		}
+	}
+	return c.cache.SetMany(ctx, entries)
+}
+
+func (c *loadingCache[K, V]) DeleteMany(ctx context.Context, keys []K) error {
+	for _, k := range keys {
+		keyStr := c.keyFn(k)
+		c.softExpiry.Delete(keyStr)
+		c.refreshing.Delete(keyStr)
+	}
+	if c.negative != nil {
+		_ = c.negative.DeleteMany(ctx, keys)
+	}
+	return c.cache.DeleteMany(ctx, keys)
+}
+
+func (c *loadingCache[K, V]) SetTagged(ctx context.Context, key K, value V, tags ...string) error {
+	c.markFresh(c.keyFn(key), time.Now(), 0)
+	if c.negative != nil {
+		_ = c.negative.Delete(ctx, key)
+	}
+	return c.cache.SetTagged(ctx, key, value, tags...)
+}
+
+func (c *loadingCache[K, V]) InvalidateTag(ctx context.Context, tag string) error {
+	keys, err := invalidateTagKeys(ctx, c.cache, tag)
+	for _, keyStr := range keys {
+		c.softExpiry.Delete(keyStr)
+	}
+	return err
+}
+
 func (c *loadingCache[K, V]) Close() error {
 	if c.negative != nil {
 		_ = c.negative.Close()
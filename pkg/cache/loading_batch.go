@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBatchWait    = 2 * time.Millisecond
+	defaultMaxBatchSize = 100
+)
+
+// batchLoader coalesces concurrent misses into BatchLoadFunc calls, in the
+// style of a dataloader. The first key enqueued opens a batch; the batch is
+// dispatched after BatchWait or as soon as it holds MaxBatchSize keys.
+//
+// A key that is already queued or being loaded is not enqueued again — the
+// caller waits on the existing call, which gives the same deduplication as
+// singleflight on the single-key path.
+type batchLoader[K comparable, V any] struct {
+	fn      BatchLoadFunc[K, V]
+	keyFn   KeyFunc[K]
+	wait    time.Duration
+	maxSize int
+
+	// store persists a finished batch before its waiters are released,
+	// so a caller that retries Get right after load observes the value.
+	store func(loaded map[K]V, absent []K, took time.Duration)
+
+	mu       sync.Mutex
+	batch    *loadBatch[K, V]         // collecting; nil when idle
+	inflight map[string]*batchCall[V] // keyStr → queued or running call
+}
+
+type loadBatch[K comparable, V any] struct {
+	// ctx is detached from the first caller: one cancellation must not abort
+	// a load that other callers are waiting on.
+	ctx   context.Context
+	keys  []K
+	calls []*batchCall[V]
+	timer *time.Timer
+}
+
+type batchCall[V any] struct {
+	done  chan struct{}
+	v     V
+	found bool
+	err   error
+}
+
+func newBatchLoader[K comparable, V any](
+	fn BatchLoadFunc[K, V],
+	keyFn KeyFunc[K],
+	cfg LoadingConfig,
+	store func(loaded map[K]V, absent []K, took time.Duration),
+) *batchLoader[K, V] {
+	if cfg.BatchWait <= 0 {
+		cfg.BatchWait = defaultBatchWait
+	}
+	if cfg.MaxBatchSize <= 0 {
+		cfg.MaxBatchSize = defaultMaxBatchSize
+	}
+	return &batchLoader[K, V]{
+		fn:       fn,
+		keyFn:    keyFn,
+		wait:     cfg.BatchWait,
+		maxSize:  cfg.MaxBatchSize,
+		store:    store,
+		inflight: make(map[string]*batchCall[V]),
+	}
+}
+
+func (b *batchLoader[K, V]) enqueue(ctx context.Context, key K) *batchCall[V] {
+	keyStr := b.keyFn(key)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if call, ok := b.inflight[keyStr]; ok {
+		return call
+	}
+
+	call := &batchCall[V]{done: make(chan struct{})}
+	b.inflight[keyStr] = call
+
+	if b.batch == nil {
+		batch := &loadBatch[K, V]{ctx: context.WithoutCancel(ctx)}
+		batch.timer = time.AfterFunc(b.wait, func() { b.dispatch(batch) })
+		b.batch = batch
+	}
+
+	batch := b.batch
+	batch.keys = append(batch.keys, key)
+	batch.calls = append(batch.calls, call)
+
+	if len(batch.keys) >= b.maxSize {
+		b.batch = nil
+		batch.timer.Stop()
+		go b.run(batch)
+	}
+	return call
+}
+
+// dispatch is the BatchWait timer callback. It is a no-op when the batch was
+// already dispatched because it reached MaxBatchSize.
+func (b *batchLoader[K, V]) dispatch(batch *loadBatch[K, V]) {
+	b.mu.Lock()
+	if b.batch != batch {
+		b.mu.Unlock()
+		return
+	}
+	b.batch = nil
+	b.mu.Unlock()
+
+	b.run(batch)
+}
+
+func (b *batchLoader[K, V]) run(batch *loadBatch[K, V]) {
+	start := time.Now()
+	loaded, err := b.fn(batch.ctx, batch.keys)
+	took := time.Since(start)
+	if errors.Is(err, ErrNotFound) {
+		loaded, err = nil, nil
+	}
+
+	if err == nil {
+		var absent []K
+		for _, k := range batch.keys {
+			if _, ok := loaded[k]; !ok {
+				absent = append(absent, k)
+			}
+		}
+		b.store(loaded, absent, took)
+	}
+
+	b.mu.Lock()
+	for _, k := range batch.keys {
+		delete(b.inflight, b.keyFn(k))
+	}
+	b.mu.Unlock()
+
+	for i, call := range batch.calls {
+		if err != nil {
+			call.err = err
+		} else {
+			call.v, call.found = loaded[batch.keys[i]]
+		}
+		close(call.done)
+	}
+}
+
+// load enqueues a single key and waits for its batch.
+// Returns ErrNotFound when the loader did not return the key.
+func (b *batchLoader[K, V]) load(ctx context.Context, key K) (V, error) {
+	call := b.enqueue(ctx, key)
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			var zero V
+			return zero, call.err
+		}
+		if !call.found {
+			var zero V
+			return zero, ErrNotFound
+		}
+		return call.v, nil
+	case <-ctx.Done():
+		var zero V
+		return zero, fmt.Errorf("cache: load cancelled: %w", ctx.Err())
+	}
+}
+
+// loadMany enqueues all keys and waits for every batch they landed in.
+// Keys the loader did not return are omitted from the result.
+func (b *batchLoader[K, V]) loadMany(ctx context.Context, keys []K) (map[K]V, error) {
+	calls := make([]*batchCall[V], len(keys))
+	for i, k := range keys {
+		calls[i] = b.enqueue(ctx, k)
+	}
+
+	var (
+		loaded = make(map[K]V, len(keys))
+		first  error
+	)
+	for i, call := range calls {
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return loaded, fmt.Errorf("cache: load cancelled: %w", ctx.Err())
+		}
+		switch {
+		case call.err != nil:
+			if first == nil {
+				first = call.err
+			}
+		case call.found:
+			loaded[keys[i]] = call.v
+		}
+	}
+	return loaded, first
+}
@@ -7,22 +7,7 @@ import (
 
 // Stats is the observability hook for cache operations.
 // Wire an implementation via Builder.WithStats().
-// Use NoopStats() when metrics are not needed.
-//
-// To integrate with OpenTelemetry:
-//
-//	type otelStats struct {
-//	    hits, misses, errors metric.Int64Counter
-//	    loadDur              metric.Float64Histogram
-//	}
-//
-//	func (s *otelStats) Hit(ctx context.Context, name, layer string) {
-//	    s.hits.Add(ctx, 1, metric.WithAttributes(
-//	        attribute.String("cache", name),
-//	        attribute.String("layer", layer),
-//	    ))
-//	}
-//	// ... implement Miss, Error, LoadDuration similarly
+// Use OTelStats() for OpenTelemetry metrics, NoopStats() when metrics are not needed.
 type Stats interface {
 	// Hit is called when a key is found in the given layer ("l1" or "l2").
 	Hit(ctx context.Context, name, layer string)
@@ -30,10 +15,11 @@ type Stats interface {
 	// For a multilevel cache: Miss("l1") means L2 may still have the value;
 	// Miss("l2") is a true cache miss across all layers.
 	Miss(ctx context.Context, name, layer string)
-	// Error is called when an operation fails. op is "get", "set", or "delete".
+	// Error is called when an operation fails. op is "get", "set", "delete",
+	// or "invalidate" for cross-instance invalidation publish/decode failures.
 	Error(ctx context.Context, name, layer, op string)
-	// LoadDuration is called after LoadFunc returns (LoadingCache only).
-	// err is nil on success.
+	// LoadDuration is called after LoadFunc or BatchLoadFunc returns
+	// (LoadingCache only). err is nil on success.
 	LoadDuration(ctx context.Context, name string, d time.Duration, err error)
 }
 
@@ -106,4 +92,57 @@ func (c *instrumentedCache[K, V]) Delete(ctx context.Context, key K) error {
 	return nil
 }
 
+// GetMany records one Hit or Miss per key, and a single Error for a failed batch.
+func (c *instrumentedCache[K, V]) GetMany(ctx context.Context, keys []K) (map[K]V, error) {
+	found, err := c.inner.GetMany(ctx, keys)
+	if err != nil {
+		c.stats.Error(ctx, c.name, c.layer, "get")
+	}
+	for _, k := range keys {
+		if _, ok := found[k]; ok {
+			c.stats.Hit(ctx, c.name, c.layer)
+		} else if err == nil {
+			c.stats.Miss(ctx, c.name, c.layer)
+		}
+	}
+	return found, err
+}
+
+func (c *instrumentedCache[K, V]) SetMany(ctx context.Context, entries map[K]V) error {
+	if err := c.inner.SetMany(ctx, entries); err != nil {
+		c.stats.Error(ctx, c.name, c.layer, "set")
+		return err
+	}
+	return nil
+}
+
+func (c *instrumentedCache[K, V]) DeleteMany(ctx context.Context, keys []K) error {
+	if err := c.inner.DeleteMany(ctx, keys); err != nil {
+		c.stats.Error(ctx, c.name, c.layer, "delete")
+		return err
+	}
+	return nil
+}
+
+func (c *instrumentedCache[K, V]) SetTagged(ctx context.Context, key K, value V, tags ...string) error {
+	if err := c.inner.SetTagged(ctx, key, value, tags...); err != nil {
+		c.stats.Error(ctx, c.name, c.layer, "set")
+		return err
+	}
+	return nil
+}
+
+func (c *instrumentedCache[K, V]) InvalidateTag(ctx context.Context, tag string) error {
+	_, err := c.invalidateTagKeys(ctx, tag)
+	return err
+}
+
+func (c *instrumentedCache[K, V]) invalidateTagKeys(ctx context.Context, tag string) ([]string, error) {
+	keys, err := invalidateTagKeys(ctx, c.inner, tag)
+	if err != nil {
+		c.stats.Error(ctx, c.name, c.layer, "delete")
+	}
+	return keys, err
+}
+
 func (c *instrumentedCache[K, V]) Close() error { return c.inner.Close() }
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const scopeName = "github.com/webitel/webitel-go-kit/pkg/cache"
+
+// Attribute keys shared by OTelStats and the tracing wrappers.
+const (
+	attrCache   = attribute.Key("cache")
+	attrLayer   = attribute.Key("layer")
+	attrOp      = attribute.Key("op")
+	attrOutcome = attribute.Key("outcome")
+	attrRank    = attribute.Key("rank")
+)
+
+// OTelStats returns a Stats implementation that records OpenTelemetry metrics.
+// When mp is nil, the global MeterProvider is used.
+//
+// Instruments:
+//
+//	cache.hits           counter    {cache, layer}
+//	cache.misses         counter    {cache, layer}
+//	cache.errors         counter    {cache, layer, op}
+//	cache.load.duration  histogram  {cache, outcome}  seconds; outcome is "ok", "not_found" or "error"
+//	cache.hot_key.reads  gauge      {cache, rank}     estimated reads of the current hot keys, rank 1 is the hottest (WithHotKeys)
+//
+// Hot keys are exported by rank rather than by key, so the series stay
+// bounded by TopK and keys, which may carry user data, stay out of the
+// metrics backend. Implement HotKeyStats on your own Stats to see the keys.
+//
+// Usage:
+//
+//	stats, err := cache.OTelStats(meterProvider)
+//	if err != nil { ... }
+//	c := cache.New[string, *pb.Contact]().Name("contact").WithStats(stats)...
+func OTelStats(mp metric.MeterProvider) (Stats, error) {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter(scopeName)
+
+	hits, err := meter.Int64Counter("cache.hits",
+		metric.WithDescription("Number of cache lookups that found the key."),
+		metric.WithUnit("{lookup}"))
+	if err != nil {
+		return nil, err
+	}
+	misses, err := meter.Int64Counter("cache.misses",
+		metric.WithDescription("Number of cache lookups that did not find the key."),
+		metric.WithUnit("{lookup}"))
+	if err != nil {
+		return nil, err
+	}
+	errs, err := meter.Int64Counter("cache.errors",
+		metric.WithDescription("Number of failed cache operations."),
+		metric.WithUnit("{operation}"))
+	if err != nil {
+		return nil, err
+	}
+	loadDur, err := meter.Float64Histogram("cache.load.duration",
+		metric.WithDescription("Duration of loader calls made on cache miss."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &otelStats{hits: hits, misses: misses, errors: errs, loadDur: loadDur, hot: make(map[string][]HotKey)}
+
+	// Observed rather than recorded, so keys that cool off stop being exported
+	// instead of keeping their last value forever.
+	_, err = meter.Int64ObservableGauge("cache.hot_key.reads",
+		metric.WithDescription("Estimated recent reads of the hottest keys of a cache."),
+		metric.WithUnit("{read}"),
+		metric.WithInt64Callback(s.observeHotKeys))
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+type otelStats struct {
+	hits, misses, errors metric.Int64Counter
+	loadDur              metric.Float64Histogram
+
+	mu  sync.Mutex
+	hot map[string][]HotKey // cache name → last reported hot keys
+}
+
+func (s *otelStats) Hit(ctx context.Context, name, layer string) {
+	s.hits.Add(ctx, 1, metric.WithAttributes(attrCache.String(name), attrLayer.String(layer)))
+}
+
+func (s *otelStats) Miss(ctx context.Context, name, layer string) {
+	s.misses.Add(ctx, 1, metric.WithAttributes(attrCache.String(name), attrLayer.String(layer)))
+}
+
+func (s *otelStats) Error(ctx context.Context, name, layer, op string) {
+	s.errors.Add(ctx, 1, metric.WithAttributes(
+		attrCache.String(name),
+		attrLayer.String(layer),
+		attrOp.String(op),
+	))
+}
+
+func (s *otelStats) LoadDuration(ctx context.Context, name string, d time.Duration, err error) {
+	s.loadDur.Record(ctx, d.Seconds(), metric.WithAttributes(
+		attrCache.String(name),
+		attrOutcome.String(loadOutcome(err)),
+	))
+}
+
+// HotKeys implements HotKeyStats.
+func (s *otelStats) HotKeys(_ context.Context, name string, keys []HotKey) {
+	s.mu.Lock()
+	s.hot[name] = keys
+	s.mu.Unlock()
+}
+
+func (s *otelStats) observeHotKeys(_ context.Context, o metric.Int64Observer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, keys := range s.hot {
+		for i, k := range keys { // hottest first
+			o.Observe(int64(k.Count), metric.WithAttributes(attrCache.String(name), attrRank.Int(i+1)))
+		}
+	}
+	return nil
+}
+
+func loadOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	default:
+		return "error"
+	}
+}
@@ -12,12 +12,27 @@ import (
 //
 // Write strategy: write-through — Set/Delete propagates to both layers.
 // L1 write failure is returned immediately without touching L2.
+// When an invalidation bus is attached, every successful write is broadcast
+// so other processes evict the key from their L1.
+//
+// InvalidateTag evicts from L1 both the keys tagged locally and the keys
+// L2 reports for the tag, since L1 entries populated from L2 carry no tags.
+//
+// With hot-key detection, every read is counted and hot keys read from L2
+// are kept in L1 for HotKeyConfig.PromoteTTL.
 type multiLevel[K comparable, V any] struct {
-	l1 Cache[K, V]
-	l2 Cache[K, V]
+	l1    Cache[K, V]
+	l1raw *ristrettoCache[K, V] // unwrapped L1, for evicting serialized keys
+	l2    Cache[K, V]
+	inv   *invalidationBus[K, V] // nil unless Builder.WithInvalidation is set
+	hot   *hotKeys[K, V]         // nil unless Builder.WithHotKeys is set
 }
 
 func (c *multiLevel[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
+	if c.hot != nil {
+		c.hot.record(key)
+	}
+
 	v, ok, err := c.l1.Get(ctx, key)
 	if err == nil && ok {
 		return v, true, nil
@@ -29,7 +44,7 @@ func (c *multiLevel[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
 	}
 
 	// Populate L1 on L2 hit; ignore error — L1 is best-effort.
-	_ = c.l1.Set(ctx, key, v)
+	c.populateL1(ctx, key, v)
 	return v, true, nil
 }
 
@@ -37,24 +52,156 @@ func (c *multiLevel[K, V]) Set(ctx context.Context, key K, value V) error {
 	if err := c.l1.Set(ctx, key, value); err != nil {
 		return err
 	}
-	return c.l2.Set(ctx, key, value)
+	if err := c.l2.Set(ctx, key, value); err != nil {
+		return err
+	}
+	c.invalidate(key)
+	return nil
 }
 
 func (c *multiLevel[K, V]) SetTTL(ctx context.Context, key K, value V, ttl time.Duration) error {
 	if err := c.l1.SetTTL(ctx, key, value, ttl); err != nil {
 		return err
 	}
-	return c.l2.SetTTL(ctx, key, value, ttl)
+	if err := c.l2.SetTTL(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	c.invalidate(key)
+	return nil
 }
 
 func (c *multiLevel[K, V]) Delete(ctx context.Context, key K) error {
 	if err := c.l1.Delete(ctx, key); err != nil {
 		return err
 	}
-	return c.l2.Delete(ctx, key)
+	if err := c.l2.Delete(ctx, key); err != nil {
+		return err
+	}
+	c.invalidate(key)
+	return nil
+}
+
+func (c *multiLevel[K, V]) GetMany(ctx context.Context, keys []K) (map[K]V, error) {
+	if c.hot != nil {
+		for _, k := range keys {
+			c.hot.record(k)
+		}
+	}
+
+	found, err := c.l1.GetMany(ctx, keys)
+	if err != nil || found == nil {
+		found = make(map[K]V, len(keys))
+	}
+
+	missed := make([]K, 0, len(keys)-len(found))
+	for _, k := range keys {
+		if _, ok := found[k]; !ok {
+			missed = append(missed, k)
+		}
+	}
+	if len(missed) == 0 {
+		return found, nil
+	}
+
+	fromL2, err := c.l2.GetMany(ctx, missed)
+	if len(fromL2) > 0 {
+		// Populate L1 on L2 hit; ignore error — L1 is best-effort.
+		if c.hot != nil {
+			for k, v := range fromL2 {
+				c.populateL1(ctx, k, v)
+			}
+		} else {
+			_ = c.l1.SetMany(ctx, fromL2)
+		}
+	}
+	for k, v := range fromL2 {
+		found[k] = v
+	}
+	return found, err
+}
+
+// populateL1 copies a value read from L2 into L1, with the extended TTL when
+// the key is hot. Errors are ignored — L1 is best-effort.
+func (c *multiLevel[K, V]) populateL1(ctx context.Context, key K, v V) {
+	if c.hot != nil {
+		if ttl := c.hot.l1TTL(key); ttl > 0 {
+			_ = c.l1.SetTTL(ctx, key, v, ttl)
+			return
+		}
+	}
+	_ = c.l1.Set(ctx, key, v)
+}
+
+func (c *multiLevel[K, V]) SetMany(ctx context.Context, entries map[K]V) error {
+	if err := c.l1.SetMany(ctx, entries); err != nil {
+		return err
+	}
+	if err := c.l2.SetMany(ctx, entries); err != nil {
+		return err
+	}
+	for k := range entries {
+		c.invalidate(k)
+	}
+	return nil
+}
+
+func (c *multiLevel[K, V]) DeleteMany(ctx context.Context, keys []K) error {
+	if err := c.l1.DeleteMany(ctx, keys); err != nil {
+		return err
+	}
+	if err := c.l2.DeleteMany(ctx, keys); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		c.invalidate(k)
+	}
+	return nil
+}
+
+func (c *multiLevel[K, V]) SetTagged(ctx context.Context, key K, value V, tags ...string) error {
+	if err := c.l1.SetTagged(ctx, key, value, tags...); err != nil {
+		return err
+	}
+	if err := c.l2.SetTagged(ctx, key, value, tags...); err != nil {
+		return err
+	}
+	c.invalidate(key)
+	return nil
+}
+
+func (c *multiLevel[K, V]) InvalidateTag(ctx context.Context, tag string) error {
+	_, err := c.invalidateTagKeys(ctx, tag)
+	return err
+}
+
+func (c *multiLevel[K, V]) invalidateTagKeys(ctx context.Context, tag string) ([]string, error) {
+	local, _ := invalidateTagKeys(ctx, c.l1, tag)
+	remote, err := invalidateTagKeys(ctx, c.l2, tag)
+	if c.l1raw != nil {
+		for _, k := range remote {
+			c.l1raw.delString(k)
+		}
+	}
+	keys := append(local, remote...)
+	if c.inv != nil {
+		c.inv.publishKeys(keys...)
+	}
+	return keys, err
+}
+
+func (c *multiLevel[K, V]) invalidate(key K) {
+	if c.inv != nil {
+		c.inv.Publish(key)
+	}
 }
 
 func (c *multiLevel[K, V]) Close() error {
+	if c.hot != nil {
+		c.hot.Close()
+	}
+	if c.inv != nil {
+		_ = c.inv.Close()
+	}
 	_ = c.l1.Close()
 	return c.l2.Close()
 }
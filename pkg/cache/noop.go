@@ -9,12 +9,13 @@ import (
 // Noop returns a Cache backed by a plain in-memory map with no TTL and no eviction.
 // Intended for unit tests — eliminates Ristretto and Redis dependencies.
 func Noop[K comparable, V any]() Cache[K, V] {
-	return &noopCache[K, V]{m: make(map[K]V)}
+	return &noopCache[K, V]{m: make(map[K]V), tags: make(map[string]map[K]struct{})}
 }
 
 type noopCache[K comparable, V any] struct {
-	mu sync.RWMutex
-	m  map[K]V
+	mu   sync.RWMutex
+	m    map[K]V
+	tags map[string]map[K]struct{}
 }
 
 func (c *noopCache[K, V]) Get(_ context.Context, key K) (V, bool, error) {
@@ -42,4 +43,59 @@ func (c *noopCache[K, V]) Delete(_ context.Context, key K) error {
 	return nil
 }
 
+func (c *noopCache[K, V]) GetMany(_ context.Context, keys []K) (map[K]V, error) {
+	found := make(map[K]V, len(keys))
+	c.mu.RLock()
+	for _, k := range keys {
+		if v, ok := c.m[k]; ok {
+			found[k] = v
+		}
+	}
+	c.mu.RUnlock()
+	return found, nil
+}
+
+func (c *noopCache[K, V]) SetMany(_ context.Context, entries map[K]V) error {
+	c.mu.Lock()
+	for k, v := range entries {
+		c.m[k] = v
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *noopCache[K, V]) DeleteMany(_ context.Context, keys []K) error {
+	c.mu.Lock()
+	for _, k := range keys {
+		delete(c.m, k)
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *noopCache[K, V]) SetTagged(_ context.Context, key K, value V, tags ...string) error {
+	c.mu.Lock()
+	c.m[key] = value
+	for _, tag := range tags {
+		members, ok := c.tags[tag]
+		if !ok {
+			members = make(map[K]struct{})
+			c.tags[tag] = members
+		}
+		members[key] = struct{}{}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *noopCache[K, V]) InvalidateTag(_ context.Context, tag string) error {
+	c.mu.Lock()
+	for k := range c.tags[tag] {
+		delete(c.m, k)
+	}
+	delete(c.tags, tag)
+	c.mu.Unlock()
+	return nil
+}
+
 func (c *noopCache[K, V]) Close() error { return nil }
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,10 +12,24 @@ import (
 // RedisConfig configures the L2 distributed Redis cache.
 type RedisConfig[V any] struct {
 	// Client is the go-redis client. Required.
-	Client *redis.Client
+	// Accepts *redis.Client, *redis.ClusterClient, *redis.Ring, a Sentinel-backed
+	// failover client, or anything returned by redis.NewUniversalClient.
+	Client redis.UniversalClient
 	// Prefix is prepended to all keys: "{prefix}:{key}".
 	// Use a unique prefix per cache to avoid key collisions.
+	// Required for *redis.ClusterClient and *redis.Ring, where it is the hash
+	// tag, see HashTag.
 	Prefix string
+	// HashTag wraps Prefix in a Redis Cluster hash tag: "{prefix}:{key}" becomes
+	// "{{prefix}}:{key}". All keys of the cache, including tag sets, then map to
+	// the same hash slot, which MGET, multi-key DEL, SetTagged and InvalidateTag
+	// require on a sharded deployment.
+	//
+	// Always enabled for *redis.ClusterClient and *redis.Ring. Set it explicitly
+	// to keep the same key layout on a standalone or Sentinel deployment, e.g.
+	// while migrating to Cluster. Note that a single slot means a single shard:
+	// split large caches across several prefixes to spread the load.
+	HashTag bool
 	// TTL is the default time-to-live for stored entries. Required.
 	TTL time.Duration
 	// Codec handles serialization to/from []byte. Required.
@@ -22,23 +37,44 @@ type RedisConfig[V any] struct {
 }
 
 type redisCache[K comparable, V any] struct {
-	client *redis.Client
-	prefix string
-	ttl    time.Duration
-	codec  Codec[V]
-	keyFn  KeyFunc[K]
+	client redis.UniversalClient
+	// prefix is RedisConfig.Prefix, wrapped in a hash tag when enabled.
+	prefix    string
+	ttl       time.Duration
+	codec     Codec[V]
+	keyFn     KeyFunc[K]
+	ttlPolicy *ttlPolicy[K, V] // set by Builder; nil keeps the fixed TTL
 }
 
 func newRedisCache[K comparable, V any](cfg RedisConfig[V], keyFn KeyFunc[K]) *redisCache[K, V] {
 	return &redisCache[K, V]{
 		client: cfg.Client,
-		prefix: cfg.Prefix,
+		prefix: redisKeyPrefix(cfg),
 		ttl:    cfg.TTL,
 		codec:  cfg.Codec,
 		keyFn:  keyFn,
 	}
 }
 
+// redisKeyPrefix returns the key prefix for cfg, wrapped in a hash tag when
+// HashTag is set or the client shards keys across nodes.
+func redisKeyPrefix[V any](cfg RedisConfig[V]) string {
+	if (cfg.HashTag || redisShardsKeys(cfg.Client)) && cfg.Prefix != "" {
+		return "{" + cfg.Prefix + "}"
+	}
+	return cfg.Prefix
+}
+
+// redisShardsKeys reports whether client spreads keys across nodes by hash
+// slot or shard, so multi-key commands need all keys under one hash tag.
+func redisShardsKeys(client redis.UniversalClient) bool {
+	switch client.(type) {
+	case *redis.ClusterClient, *redis.Ring:
+		return true
+	}
+	return false
+}
+
 func (c *redisCache[K, V]) key(k K) string {
 	return fmt.Sprintf("%s:%s", c.prefix, c.keyFn(k))
 }
@@ -53,7 +89,11 @@ func (c *redisCache[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
 		var zero V
 		return zero, false, err
 	}
-	v, err := c.codec.Unmarshal(data)
+	v, err := c.decode(ctx, c.keyFn(key), data)
+	if errors.Is(err, ErrVersionMismatch) {
+		var zero V
+		return zero, false, nil
+	}
 	if err != nil {
 		var zero V
 		return zero, false, err
@@ -62,11 +102,16 @@ func (c *redisCache[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
 }
 
 func (c *redisCache[K, V]) Set(ctx context.Context, key K, value V) error {
-	return c.SetTTL(ctx, key, value, c.ttl)
+	return c.SetTTL(ctx, key, value, c.entryTTL(key, value))
+}
+
+// entryTTL returns the TTL for a write without an explicit TTL.
+func (c *redisCache[K, V]) entryTTL(key K, value V) time.Duration {
+	return c.ttlPolicy.ttl(c.ttl, 0, key, value)
 }
 
 func (c *redisCache[K, V]) SetTTL(ctx context.Context, key K, value V, ttl time.Duration) error {
-	data, err := c.codec.Marshal(value)
+	data, err := c.encode(ctx, value)
 	if err != nil {
 		return err
 	}
@@ -77,4 +122,186 @@ func (c *redisCache[K, V]) Delete(ctx context.Context, key K) error {
 	return c.client.Del(ctx, c.key(key)).Err()
 }
 
+// GetMany reads all keys with a single MGET (a single hash slot on Cluster, see
+// RedisConfig.HashTag). Entries that fail to decode are skipped and their
+// errors joined into the returned error; entries written with another
+// Versioned schema are skipped as misses.
+func (c *redisCache[K, V]) GetMany(ctx context.Context, keys []K) (map[K]V, error) {
+	found := make(map[K]V, len(keys))
+	if len(keys) == 0 {
+		return found, nil
+	}
+
+	vals, err := c.client.MGet(ctx, c.keys(keys)...).Result()
+	if err != nil {
+		return found, err
+	}
+
+	var errs []error
+	for i, raw := range vals {
+		s, ok := raw.(string)
+		if !ok {
+			continue // nil — key is absent
+		}
+		v, err := c.decode(ctx, c.keyFn(keys[i]), []byte(s))
+		if errors.Is(err, ErrVersionMismatch) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		found[keys[i]] = v
+	}
+	return found, errors.Join(errs...)
+}
+
+// SetMany writes all entries in one pipelined round trip.
+func (c *redisCache[K, V]) SetMany(ctx context.Context, entries map[K]V) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	_, err := c.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for k, v := range entries {
+			data, err := c.encode(ctx, v)
+			if err != nil {
+				return err
+			}
+			p.Set(ctx, c.key(k), data, c.entryTTL(k, v))
+		}
+		return nil
+	})
+	return err
+}
+
+func (c *redisCache[K, V]) DeleteMany(ctx context.Context, keys []K) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, c.keys(keys)...).Err()
+}
+
+// SetTagged writes the entry and adds its key to one Redis set per tag
+// ("{prefix}:__tag__:{tag}") in a single MULTI/EXEC. Tag sets expire with the
+// entry TTL, refreshed on every write, so they never outlive their members.
+// With a TTLFunc or jitter, entry TTLs differ and the tag set TTL is only
+// ever extended, by extendTagTTLScript, to cover its longest-lived member.
+// Without a TTL a tag set never expires; instead every write checks a few
+// random members and removes those whose entry is gone (deleted or evicted),
+// which keeps the set close to its live members.
+func (c *redisCache[K, V]) SetTagged(ctx context.Context, key K, value V, tags ...string) error {
+	data, err := c.encode(ctx, value)
+	if err != nil {
+		return err
+	}
+	keyStr, ttl := c.keyFn(key), c.entryTTL(key, value)
+	_, err = c.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Set(ctx, c.key(key), data, ttl)
+		for _, tag := range tags {
+			tagKey := c.tagKey(tag)
+			p.SAdd(ctx, tagKey, keyStr)
+			switch {
+			case ttl <= 0:
+				pruneTagScript.Eval(ctx, p, []string{tagKey}, c.prefix+":", tagPruneSample)
+			case c.ttlPolicy.variable():
+				extendTagTTLScript.Eval(ctx, p, []string{tagKey}, ttl.Milliseconds())
+			default:
+				p.Expire(ctx, tagKey, ttl)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// extendTagTTLScript sets the TTL of the tag set KEYS[1] to ARGV[1]
+// milliseconds unless it already lives longer. It stands in for
+// EXPIRE NX + EXPIRE GT, which need Redis 7.
+var extendTagTTLScript = redis.NewScript(`
+if redis.call('PTTL', KEYS[1]) < tonumber(ARGV[1]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// tagPruneSample is the number of tag set members pruneTagScript checks per
+// write. A set settles at about 1/tagPruneSample dead members.
+const tagPruneSample = 16
+
+// pruneTagScript checks ARGV[2] random members of the tag set KEYS[1] and
+// removes those whose entry (ARGV[1] prefixed to the member) no longer
+// exists. Like invalidateTagScript, it relies on the entries sharing the tag
+// set's hash slot on Cluster.
+var pruneTagScript = redis.NewScript(`
+for _, member in ipairs(redis.call('SRANDMEMBER', KEYS[1], ARGV[2])) do
+	if redis.call('EXISTS', ARGV[1] .. member) == 0 then
+		redis.call('SREM', KEYS[1], member)
+	end
+end
+return 0
+`)
+
+func (c *redisCache[K, V]) InvalidateTag(ctx context.Context, tag string) error {
+	_, err := c.invalidateTagKeys(ctx, tag)
+	return err
+}
+
+// invalidateTagScript deletes every member of the tag set (KEYS[1]) prefixed
+// with ARGV[1], then the set itself, and returns the members. Runs atomically
+// so a concurrent SetTagged is either fully invalidated or fully kept.
+// On Cluster the member keys share the tag set's hash slot (RedisConfig.HashTag),
+// so the script runs on the node that owns all of them.
+var invalidateTagScript = redis.NewScript(`
+local members = redis.call('SMEMBERS', KEYS[1])
+for i = 1, #members, 500 do
+	local batch = {}
+	for j = i, math.min(i + 499, #members) do
+		batch[#batch + 1] = ARGV[1] .. members[j]
+	end
+	redis.call('DEL', unpack(batch))
+end
+redis.call('DEL', KEYS[1])
+return members
+`)
+
+func (c *redisCache[K, V]) invalidateTagKeys(ctx context.Context, tag string) ([]string, error) {
+	return invalidateTagScript.Run(ctx, c.client, []string{c.tagKey(tag)}, c.prefix+":").StringSlice()
+}
+
+func (c *redisCache[K, V]) tagKey(tag string) string {
+	return fmt.Sprintf("%s:__tag__:%s", c.prefix, tag)
+}
+
+// encode marshals v, adding entry metadata when the codec is an Envelope.
+func (c *redisCache[K, V]) encode(ctx context.Context, v V) ([]byte, error) {
+	if mc, ok := c.codec.(metaCodec[V]); ok {
+		return mc.marshalMeta(v, entryMeta{WrittenAt: time.Now(), Delta: loadDeltaFrom(ctx)})
+	}
+	return c.codec.Marshal(v)
+}
+
+// decode unmarshals data and, when the codec is an Envelope, reports the
+// entry metadata to the metaSink carried by ctx.
+func (c *redisCache[K, V]) decode(ctx context.Context, keyStr string, data []byte) (V, error) {
+	mc, ok := c.codec.(metaCodec[V])
+	if !ok {
+		return c.codec.Unmarshal(data)
+	}
+	v, meta, err := mc.unmarshalMeta(data)
+	if err == nil {
+		if sink := metaSinkFrom(ctx); sink != nil {
+			sink.record(keyStr, meta)
+		}
+	}
+	return v, err
+}
+
+func (c *redisCache[K, V]) keys(ks []K) []string {
+	out := make([]string, len(ks))
+	for i, k := range ks {
+		out[i] = c.key(k)
+	}
+	return out
+}
+
 func (c *redisCache[K, V]) Close() error { return nil }
@@ -19,12 +19,15 @@ type RistrettoConfig struct {
 	// BufferItems is the size of the Get result buffer per shard. Default: 64.
 	BufferItems int64
 	// TTL is the per-entry expiration duration.
-	// When used with L2, keep L1 TTL shorter than L2 TTL to bound staleness
-	// without requiring pub/sub invalidation across nodes.
+	// When used with L2, keep L1 TTL shorter than L2 TTL to bound staleness,
+	// or enable Builder.WithInvalidation to evict stale entries across nodes.
 	TTL time.Duration
 	// DefaultCost is the cost charged per entry when SetTTL is called.
-	// Defaults to 1 if zero.
+	// Defaults to 1 if zero. Overridden by Builder.WithCostFunc.
 	DefaultCost int64
+	// TrackKeys keeps the set of resident keys so Snapshot can enumerate L1.
+	// Costs one map entry (and a copy of the serialized key) per cached entry.
+	TrackKeys bool
 }
 
 // ristrettoCache is the L1 implementation backed by Ristretto.
@@ -35,6 +38,14 @@ type ristrettoCache[K comparable, V any] struct {
 	keyFn       KeyFunc[K]
 	defaultCost int64
 	ttl         time.Duration
+	tags        *tagIndex
+	resident    *residentKeys // nil unless RistrettoConfig.TrackKeys is set
+	codec       Codec[V]      // used by Snapshot/Restore; nil disables them
+
+	// Set by Builder; nil/zero keep the fixed TTL and DefaultCost.
+	ttlPolicy *ttlPolicy[K, V]
+	ttlLimit  time.Duration // caps TTLFunc results when L2 is configured
+	costFn    CostFunc[V]
 }
 
 func newRistretto[K comparable, V any](cfg RistrettoConfig, keyFn KeyFunc[K]) (*ristrettoCache[K, V], error) {
@@ -48,11 +59,23 @@ func newRistretto[K comparable, V any](cfg RistrettoConfig, keyFn KeyFunc[K]) (*
 		cfg.DefaultCost = 1
 	}
 
-	inner, err := ristretto.NewCache(&ristretto.Config[string, V]{
+	rcfg := &ristretto.Config[string, V]{
 		NumCounters: cfg.NumCounters,
 		MaxCost:     cfg.MaxCost,
 		BufferItems: cfg.BufferItems,
-	})
+	}
+	tags := newTagIndex()
+	var resident *residentKeys
+	if cfg.TrackKeys {
+		resident = newResidentKeys()
+	}
+	forget := func(item *ristretto.Item[V]) {
+		tags.removeHash(item.Key)
+		resident.removeHash(item.Key)
+	}
+	rcfg.OnEvict, rcfg.OnReject = forget, forget
+
+	inner, err := ristretto.NewCache(rcfg)
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +85,8 @@ func newRistretto[K comparable, V any](cfg RistrettoConfig, keyFn KeyFunc[K]) (*
 		keyFn:       keyFn,
 		defaultCost: cfg.DefaultCost,
 		ttl:         cfg.TTL,
+		tags:        tags,
+		resident:    resident,
 	}, nil
 }
 
@@ -71,24 +96,97 @@ func (c *ristrettoCache[K, V]) Get(_ context.Context, key K) (V, bool, error) {
 }
 
 func (c *ristrettoCache[K, V]) Set(_ context.Context, key K, value V) error {
-	if c.ttl > 0 {
-		c.inner.SetWithTTL(c.keyFn(key), value, c.defaultCost, c.ttl)
-	} else {
-		c.inner.Set(c.keyFn(key), value, c.defaultCost)
-	}
+	c.set(c.keyFn(key), value, c.entryTTL(key, value))
 	return nil
 }
 
 func (c *ristrettoCache[K, V]) SetTTL(_ context.Context, key K, value V, ttl time.Duration) error {
-	c.inner.SetWithTTL(c.keyFn(key), value, c.defaultCost, ttl)
+	c.set(c.keyFn(key), value, ttl)
 	return nil
 }
 
+// set stores value under its serialized key; a zero ttl means no expiry.
+func (c *ristrettoCache[K, V]) set(keyStr string, value V, ttl time.Duration) {
+	c.inner.SetWithTTL(keyStr, value, c.cost(value), ttl)
+	c.resident.add(keyStr)
+}
+
+// entryTTL returns the TTL for a write without an explicit TTL.
+func (c *ristrettoCache[K, V]) entryTTL(key K, value V) time.Duration {
+	return c.ttlPolicy.ttl(c.ttl, c.ttlLimit, key, value)
+}
+
+func (c *ristrettoCache[K, V]) cost(value V) int64 {
+	if c.costFn != nil {
+		return c.costFn(value)
+	}
+	return c.defaultCost
+}
+
 func (c *ristrettoCache[K, V]) Delete(_ context.Context, key K) error {
-	c.inner.Del(c.keyFn(key))
+	c.delString(c.keyFn(key))
+	return nil
+}
+
+func (c *ristrettoCache[K, V]) GetMany(_ context.Context, keys []K) (map[K]V, error) {
+	found := make(map[K]V, len(keys))
+	for _, k := range keys {
+		if v, ok := c.inner.Get(c.keyFn(k)); ok {
+			found[k] = v
+		}
+	}
+	return found, nil
+}
+
+func (c *ristrettoCache[K, V]) SetMany(ctx context.Context, entries map[K]V) error {
+	for k, v := range entries {
+		_ = c.Set(ctx, k, v)
+	}
+	return nil
+}
+
+func (c *ristrettoCache[K, V]) DeleteMany(_ context.Context, keys []K) error {
+	for _, k := range keys {
+		c.delString(c.keyFn(k))
+	}
+	return nil
+}
+
+func (c *ristrettoCache[K, V]) SetTagged(_ context.Context, key K, value V, tags ...string) error {
+	keyStr := c.keyFn(key)
+	c.set(keyStr, value, c.entryTTL(key, value))
+	c.tags.add(keyStr, tags)
 	return nil
 }
 
+func (c *ristrettoCache[K, V]) InvalidateTag(ctx context.Context, tag string) error {
+	_, err := c.invalidateTagKeys(ctx, tag)
+	return err
+}
+
+func (c *ristrettoCache[K, V]) invalidateTagKeys(_ context.Context, tag string) ([]string, error) {
+	keys := c.tags.take(tag)
+	for _, k := range keys {
+		c.delString(k)
+	}
+	return keys, nil
+}
+
+// delString evicts an entry by its serialized key.
+// Used by the invalidation bus, which only sees keys already passed through keyFn.
+func (c *ristrettoCache[K, V]) delString(key string) {
+	c.inner.Del(key)
+	c.tags.remove(key)
+	c.resident.remove(key)
+}
+
+// clear drops every entry.
+func (c *ristrettoCache[K, V]) clear() {
+	c.inner.Clear()
+	c.tags.reset()
+	c.resident.reset()
+}
+
 func (c *ristrettoCache[K, V]) Close() error {
 	c.inner.Close()
 	return nil
@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// RowChange is a row-level change event published by Postgres with NOTIFY,
+// typically from a trigger:
+//
+//	PERFORM pg_notify('cache_invalidate', json_build_object(
+//	    'table', TG_TABLE_NAME, 'id', OLD.id, 'domain', OLD.domain_id)::text);
+//
+// ID and Domain accept JSON strings or numbers.
+type RowChange struct {
+	Table  string
+	ID     string
+	Domain string
+}
+
+// RowRoute maps a RowChange of one table to invalidations of one cache.
+type RowRoute[K comparable] struct {
+	// Key returns the cache key to delete, or false to skip the Delete.
+	Key func(RowChange) (K, bool)
+	// Tags returns the tags to invalidate, e.g. a per-domain tag.
+	Tags func(RowChange) []string
+}
+
+// RowInvalidator routes Postgres row-change notifications to the caches that
+// mirror those rows. It implements the notification handler of the pgw
+// package, so it can be attached to a PoolManager directly:
+//
+//	inv := cache.NewRowInvalidator()
+//	cache.OnRowChange(inv, "contacts", contacts, cache.RowRoute[int64]{
+//	    Key:  cache.RowID,
+//	    Tags: func(rc cache.RowChange) []string { return []string{"domain:" + rc.Domain} },
+//	})
+//	err := manager.BridgeNotifications(ctx, "cache_invalidate", inv)
+//
+// When this process may have lost notifications (its LISTEN connection was
+// down), the in-process state of every registered cache is cleared — see
+// HandleGap.
+type RowInvalidator struct {
+	mu      sync.RWMutex
+	routes  map[string][]func(ctx context.Context, rc RowChange) error
+	flushes []rowFlush
+	flushL2 bool
+}
+
+// rowFlush resyncs one registered cache after a gap.
+type rowFlush struct {
+	local func(ctx context.Context) error // in-process layers only
+	full  func(ctx context.Context) error // every layer, see Flush
+}
+
+// NewRowInvalidator returns an empty RowInvalidator.
+func NewRowInvalidator() *RowInvalidator {
+	return &RowInvalidator{routes: make(map[string][]func(context.Context, RowChange) error)}
+}
+
+// WithL2Flush makes HandleGap Flush every registered cache — L2 included,
+// and with WithInvalidation the L1 of every other process — instead of
+// clearing only the in-process layers. Use it when a shared L2 may have been
+// left stale by changes missed during the gap and no other process deletes
+// those keys, e.g. a single writer that is also the only listener.
+func (r *RowInvalidator) WithL2Flush() *RowInvalidator {
+	r.mu.Lock()
+	r.flushL2 = true
+	r.mu.Unlock()
+	return r
+}
+
+// OnRowChange registers c for changes of table. A cache may be registered for
+// several tables and a table may feed several caches.
+func OnRowChange[K comparable, V any](r *RowInvalidator, table string, c Cache[K, V], route RowRoute[K]) {
+	apply := func(ctx context.Context, rc RowChange) error {
+		var errs []error
+		if route.Key != nil {
+			if key, ok := route.Key(rc); ok {
+				errs = append(errs, c.Delete(ctx, key))
+			}
+		}
+		if route.Tags != nil {
+			for _, tag := range route.Tags(rc) {
+				errs = append(errs, c.InvalidateTag(ctx, tag))
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	r.mu.Lock()
+	r.routes[table] = append(r.routes[table], apply)
+	r.flushes = append(r.flushes, rowFlush{
+		local: func(ctx context.Context) error { return flushLocal(ctx, c) },
+		full:  func(ctx context.Context) error { return Flush(ctx, c) },
+	})
+	r.mu.Unlock()
+}
+
+// RowID is a RowRoute.Key for caches keyed by a numeric row ID.
+func RowID(rc RowChange) (int64, bool) {
+	id, err := strconv.ParseInt(rc.ID, 10, 64)
+	return id, err == nil
+}
+
+// RowIDString is a RowRoute.Key for caches keyed by the row ID as a string.
+func RowIDString(rc RowChange) (string, bool) {
+	return rc.ID, rc.ID != ""
+}
+
+// HandleNotification decodes a NOTIFY payload and applies it to every cache
+// registered for its table. Payloads for unregistered tables are ignored.
+func (r *RowInvalidator) HandleNotification(ctx context.Context, _ string, payload string) error {
+	rc, err := parseRowChange(payload)
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	routes := r.routes[rc.Table]
+	r.mu.RUnlock()
+
+	var errs []error
+	for _, apply := range routes {
+		errs = append(errs, apply(ctx, rc))
+	}
+	return errors.Join(errs...)
+}
+
+// HandleGap clears the in-process state of every registered cache: L1 and
+// the negative and soft-TTL state of a loading cache. The gap belongs to this
+// process only — other replicas kept listening — so nothing is published on
+// the invalidation bus and L2 is left as is, unless WithL2Flush is set.
+func (r *RowInvalidator) HandleGap(ctx context.Context, _ string) error {
+	r.mu.RLock()
+	flushes, flushL2 := r.flushes, r.flushL2
+	r.mu.RUnlock()
+
+	var errs []error
+	for _, f := range flushes {
+		if flushL2 {
+			errs = append(errs, f.full(ctx))
+		} else {
+			errs = append(errs, f.local(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type rowChangeJSON struct {
+	Table  string          `json:"table"`
+	ID     json.RawMessage `json:"id"`
+	Domain json.RawMessage `json:"domain"`
+}
+
+func parseRowChange(payload string) (RowChange, error) {
+	var raw rowChangeJSON
+	if err := json.Unmarshal([]byte(payload), &raw); err != nil {
+		return RowChange{}, fmt.Errorf("cache: row change payload: %w", err)
+	}
+	if raw.Table == "" {
+		return RowChange{}, errors.New("cache: row change payload: table is required")
+	}
+	return RowChange{
+		Table:  raw.Table,
+		ID:     jsonScalar(raw.ID),
+		Domain: jsonScalar(raw.Domain),
+	}, nil
+}
+
+// jsonScalar returns a JSON string or number as a plain string; "" for null or absent.
+func jsonScalar(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestParseRowChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    RowChange
+		wantErr bool
+	}{
+		{
+			name:    "numeric ids",
+			payload: `{"table":"contacts","id":42,"domain":1}`,
+			want:    RowChange{Table: "contacts", ID: "42", Domain: "1"},
+		},
+		{
+			name:    "string ids",
+			payload: `{"table":"contacts","id":"a-1","domain":"d"}`,
+			want:    RowChange{Table: "contacts", ID: "a-1", Domain: "d"},
+		},
+		{
+			name:    "big numeric id keeps its digits",
+			payload: `{"table":"events","id":9007199254740993}`,
+			want:    RowChange{Table: "events", ID: "9007199254740993"},
+		},
+		{
+			name:    "null and absent",
+			payload: `{"table":"contacts","id":null}`,
+			want:    RowChange{Table: "contacts"},
+		},
+		{name: "missing table", payload: `{"id":1}`, wantErr: true},
+		{name: "not JSON", payload: `contacts:1`, wantErr: true},
+		{name: "empty", payload: ``, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRowChange(tt.payload)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseRowChange(%q) = %+v, want error", tt.payload, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRowChange(%q): %v", tt.payload, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseRowChange(%q) = %+v, want %+v", tt.payload, got, tt.want)
+			}
+		})
+	}
+}
+
+// publishCounter is a Redis client that only counts PUBLISH calls.
+type publishCounter struct {
+	redis.UniversalClient
+	publishes int
+}
+
+func (c *publishCounter) Publish(ctx context.Context, _ string, _ any) *redis.IntCmd {
+	c.publishes++
+	return redis.NewIntCmd(ctx)
+}
+
+func TestRowInvalidatorHandleGap(t *testing.T) {
+	ctx := context.Background()
+	newCache := func() (*multiLevel[int64, string], *publishCounter) {
+		client := &publishCounter{}
+		c := &multiLevel[int64, string]{
+			l1:  Noop[int64, string](),
+			l2:  Noop[int64, string](),
+			inv: &invalidationBus[int64, string]{client: client, channel: "inv", origin: "test"},
+		}
+		_ = c.l1.Set(ctx, 1, "a")
+		_ = c.l2.Set(ctx, 1, "a")
+		return c, client
+	}
+	cached := func(c Cache[int64, string]) bool {
+		_, ok, _ := c.Get(ctx, 1)
+		return ok
+	}
+
+	t.Run("local only", func(t *testing.T) {
+		c, client := newCache()
+		inv := NewRowInvalidator()
+		OnRowChange(inv, "contacts", Cache[int64, string](c), RowRoute[int64]{Key: RowID})
+
+		if err := inv.HandleGap(ctx, "cache_invalidate"); err != nil {
+			t.Fatalf("HandleGap: %v", err)
+		}
+		if cached(c.l1) {
+			t.Error("L1 entry survived the gap")
+		}
+		if !cached(c.l2) {
+			t.Error("L2 entry was flushed without WithL2Flush")
+		}
+		if client.publishes != 0 {
+			t.Errorf("gap published %d invalidation messages, want 0", client.publishes)
+		}
+	})
+
+	t.Run("with L2 flush", func(t *testing.T) {
+		c, client := newCache()
+		inv := NewRowInvalidator().WithL2Flush()
+		OnRowChange(inv, "contacts", Cache[int64, string](c), RowRoute[int64]{Key: RowID})
+
+		if err := inv.HandleGap(ctx, "cache_invalidate"); err != nil {
+			t.Fatalf("HandleGap: %v", err)
+		}
+		if cached(c.l1) || cached(c.l2) {
+			t.Error("entries survived a full flush")
+		}
+		if client.publishes != 1 {
+			t.Errorf("full flush published %d invalidation messages, want 1", client.publishes)
+		}
+	})
+}
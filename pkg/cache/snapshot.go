@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2/z"
+)
+
+// ErrSnapshotUnsupported is returned by Snapshot and Restore when the cache
+// has no L1 layer built with RistrettoConfig.TrackKeys, or no codec to
+// serialize values with (see Builder.WithSnapshotCodec).
+var ErrSnapshotUnsupported = errors.New("cache: snapshot requires an L1 layer with TrackKeys and a codec")
+
+// Snapshot writes every live L1 entry of c to w, serialized with the
+// configured Codec, along with its remaining TTL.
+// Call it on shutdown and feed the output to Restore on the next start, so a
+// new pod begins with the previous pod's hot keys instead of a cold L1.
+//
+// Example:
+//
+//	f, _ := os.Create("/var/cache/contact.snap")
+//	defer f.Close()
+//	err := cache.Snapshot(contacts, f)
+func Snapshot[K comparable, V any](c Cache[K, V], w io.Writer) error {
+	s, ok := c.(snapshotter)
+	if !ok {
+		return ErrSnapshotUnsupported
+	}
+	return s.snapshot(w)
+}
+
+// Restore loads entries written by Snapshot into the L1 layer of c.
+// Entries that expired in the meantime, or were written with another
+// Versioned schema, are skipped. L2 is not touched.
+func Restore[K comparable, V any](c Cache[K, V], r io.Reader) error {
+	s, ok := c.(snapshotter)
+	if !ok {
+		return ErrSnapshotUnsupported
+	}
+	return s.restore(r)
+}
+
+// snapshotter is implemented by ristrettoCache and by the wrappers that can
+// reach it.
+type snapshotter interface {
+	snapshot(w io.Writer) error
+	restore(r io.Reader) error
+}
+
+func (c *instrumentedCache[K, V]) snapshot(w io.Writer) error { return Snapshot(c.inner, w) }
+func (c *instrumentedCache[K, V]) restore(r io.Reader) error  { return Restore(c.inner, r) }
+
+func (c *multiLevel[K, V]) snapshot(w io.Writer) error { return Snapshot[K, V](c.l1raw, w) }
+func (c *multiLevel[K, V]) restore(r io.Reader) error  { return Restore[K, V](c.l1raw, r) }
+
+func (c *loadingCache[K, V]) snapshot(w io.Writer) error { return Snapshot(c.cache, w) }
+func (c *loadingCache[K, V]) restore(r io.Reader) error  { return Restore(c.cache, r) }
+
+// snapshotMagic starts every snapshot stream; see envelopeMagic.
+var snapshotMagic = []byte{0x00, 'w', 'c', 's'}
+
+const (
+	snapshotFormatV1 byte = 1
+	// maxSnapshotFieldLen guards Restore against allocating for a corrupt length.
+	maxSnapshotFieldLen = 256 << 20
+)
+
+var errSnapshotFormat = errors.New("cache: malformed snapshot")
+
+// Snapshot stream layout: magic | format, then one record per entry until EOF:
+//
+//	uvarint len(key) | key | varint expiry (unix nanos, 0 = none) | uvarint len(value) | value
+func (c *ristrettoCache[K, V]) snapshot(w io.Writer) error {
+	if c.resident == nil || c.codec == nil {
+		return ErrSnapshotUnsupported
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(append(snapshotMagic, snapshotFormatV1)); err != nil {
+		return err
+	}
+
+	var rec []byte
+	now := time.Now()
+	for _, key := range c.resident.list() {
+		v, ok := c.inner.Get(key)
+		if !ok {
+			c.resident.remove(key)
+			continue
+		}
+		ttl, ok := c.inner.GetTTL(key)
+		if !ok {
+			continue
+		}
+		var expiry int64
+		if ttl > 0 {
+			expiry = now.Add(ttl).UnixNano()
+		}
+		data, err := c.codec.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("cache: snapshot key %q: %w", key, err)
+		}
+
+		rec = binary.AppendUvarint(rec[:0], uint64(len(key)))
+		rec = append(rec, key...)
+		rec = binary.AppendVarint(rec, expiry)
+		rec = binary.AppendUvarint(rec, uint64(len(data)))
+		if _, err := bw.Write(rec); err != nil {
+			return err
+		}
+		if _, err := bw.Write(data); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func (c *ristrettoCache[K, V]) restore(r io.Reader) error {
+	if c.codec == nil {
+		return ErrSnapshotUnsupported
+	}
+
+	br := bufio.NewReader(r)
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return fmt.Errorf("%w: %w", errSnapshotFormat, err)
+	}
+	if string(header[:len(snapshotMagic)]) != string(snapshotMagic) || header[len(snapshotMagic)] != snapshotFormatV1 {
+		return errSnapshotFormat
+	}
+
+	now := time.Now()
+	for {
+		key, err := readSnapshotBytes(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		expiry, err := binary.ReadVarint(br)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errSnapshotFormat, err)
+		}
+		data, err := readSnapshotBytes(br)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errSnapshotFormat, err)
+		}
+
+		var ttl time.Duration
+		if expiry != 0 {
+			if ttl = time.Unix(0, expiry).Sub(now); ttl <= 0 {
+				continue
+			}
+		}
+		v, err := c.codec.Unmarshal(data)
+		if errors.Is(err, ErrVersionMismatch) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cache: restore key %q: %w", key, err)
+		}
+		keyStr := string(key)
+		c.set(keyStr, v, ttl)
+	}
+
+	c.inner.Wait()
+	return nil
+}
+
+// readSnapshotBytes reads a uvarint length followed by that many bytes.
+// Returns io.EOF only when the stream ends cleanly before the length.
+func readSnapshotBytes(br *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxSnapshotFieldLen {
+		return nil, errSnapshotFormat
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(br, buf); err != nil {
+		return nil, fmt.Errorf("%w: %w", errSnapshotFormat, err)
+	}
+	return buf, nil
+}
+
+// residentKeys tracks the serialized keys currently held by Ristretto, which
+// stores only key hashes and cannot enumerate its contents.
+// Keys are added on write and removed on delete, eviction, and rejection by
+// the admission policy; Snapshot re-checks every key, so a stale member only
+// costs memory until the next Snapshot. A nil *residentKeys tracks nothing.
+type residentKeys struct {
+	mu   sync.Mutex
+	keys map[uint64]string // ristretto key hash → keyStr
+}
+
+func newResidentKeys() *residentKeys {
+	return &residentKeys{keys: make(map[uint64]string)}
+}
+
+func (r *residentKeys) add(keyStr string) {
+	if r == nil {
+		return
+	}
+	h, _ := z.KeyToHash(keyStr)
+	r.mu.Lock()
+	r.keys[h] = keyStr
+	r.mu.Unlock()
+}
+
+func (r *residentKeys) remove(keyStr string) {
+	if r == nil {
+		return
+	}
+	h, _ := z.KeyToHash(keyStr)
+	r.removeHash(h)
+}
+
+// removeHash is called from Ristretto's OnEvict and OnReject callbacks,
+// which only report the key hash.
+func (r *residentKeys) removeHash(h uint64) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	delete(r.keys, h)
+	r.mu.Unlock()
+}
+
+func (r *residentKeys) reset() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	clear(r.keys)
+	r.mu.Unlock()
+}
+
+func (r *residentKeys) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]string, 0, len(r.keys))
+	for _, k := range r.keys {
+		out = append(out, k)
+	}
+	return out
+}
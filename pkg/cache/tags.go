@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/dgraph-io/ristretto/v2/z"
+)
+
+// tagKeyInvalidator is implemented by layers that can report which keys an
+// InvalidateTag call removed, serialized with KeyFunc. multiLevel uses it to
+// evict the same keys from L1 and to broadcast them to other processes —
+// including keys whose L1 copy was populated from L2 without tag information.
+type tagKeyInvalidator interface {
+	invalidateTagKeys(ctx context.Context, tag string) ([]string, error)
+}
+
+// invalidateTagKeys calls the layer's tagKeyInvalidator when it has one and
+// falls back to a plain InvalidateTag (reporting no keys) otherwise.
+func invalidateTagKeys[K comparable, V any](ctx context.Context, c Cache[K, V], tag string) ([]string, error) {
+	if ti, ok := c.(tagKeyInvalidator); ok {
+		return ti.invalidateTagKeys(ctx, tag)
+	}
+	return nil, c.InvalidateTag(ctx, tag)
+}
+
+// tagIndex is the in-process tag → keys index used by L1.
+//
+// Members are dropped when their entry is deleted, evicted, rejected or
+// expired (Ristretto's OnEvict and OnReject report them by key hash), and
+// all at once on clear, so the index only holds resident entries. An eviction
+// racing with a rewrite of the same key may drop the new members; that entry
+// then misses a tag invalidation until it is rewritten or expires.
+type tagIndex struct {
+	mu   sync.Mutex
+	tags map[string]map[uint64]struct{} // tag → key hashes
+	keys map[uint64]taggedKey           // key hash → entry
+}
+
+type taggedKey struct {
+	keyStr string
+	tags   []string
+}
+
+func newTagIndex() *tagIndex {
+	return &tagIndex{
+		tags: make(map[string]map[uint64]struct{}),
+		keys: make(map[uint64]taggedKey),
+	}
+}
+
+// add records keyStr under tags, replacing the tags of a previous write.
+func (x *tagIndex) add(keyStr string, tags []string) {
+	h, _ := z.KeyToHash(keyStr)
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.untag(h)
+	if len(tags) == 0 {
+		return
+	}
+	for _, tag := range tags {
+		members, ok := x.tags[tag]
+		if !ok {
+			members = make(map[uint64]struct{})
+			x.tags[tag] = members
+		}
+		members[h] = struct{}{}
+	}
+	x.keys[h] = taggedKey{keyStr: keyStr, tags: slices.Clone(tags)}
+}
+
+// take removes tag from the index and returns its members.
+func (x *tagIndex) take(tag string) []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	members := x.tags[tag]
+	delete(x.tags, tag)
+	keys := make([]string, 0, len(members))
+	for h := range members {
+		e := x.keys[h]
+		keys = append(keys, e.keyStr)
+		if e.tags = slices.DeleteFunc(e.tags, func(t string) bool { return t == tag }); len(e.tags) == 0 {
+			delete(x.keys, h)
+		} else {
+			x.keys[h] = e
+		}
+	}
+	return keys
+}
+
+func (x *tagIndex) remove(keyStr string) {
+	h, _ := z.KeyToHash(keyStr)
+	x.removeHash(h)
+}
+
+// removeHash is called from Ristretto's OnEvict and OnReject callbacks,
+// which only report the key hash.
+func (x *tagIndex) removeHash(h uint64) {
+	x.mu.Lock()
+	x.untag(h)
+	x.mu.Unlock()
+}
+
+func (x *tagIndex) reset() {
+	x.mu.Lock()
+	clear(x.tags)
+	clear(x.keys)
+	x.mu.Unlock()
+}
+
+// untag drops the entry with key hash h from all its tags. x.mu must be held.
+func (x *tagIndex) untag(h uint64) {
+	e, ok := x.keys[h]
+	if !ok {
+		return
+	}
+	for _, tag := range e.tags {
+		delete(x.tags[tag], h)
+		if len(x.tags[tag]) == 0 {
+			delete(x.tags, tag)
+		}
+	}
+	delete(x.keys, h)
+}
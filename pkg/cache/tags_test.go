@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestTagIndex(t *testing.T) {
+	x := newTagIndex()
+	x.add("k1", []string{"a", "b"})
+	x.add("k2", []string{"a"})
+	x.add("k3", []string{"c"})
+
+	x.remove("k1")
+	if got := x.take("b"); len(got) != 0 {
+		t.Errorf("take(b) after removing k1 = %v, want none", got)
+	}
+	// A rewrite replaces the tags of the previous write.
+	x.add("k2", []string{"b"})
+	if got := x.take("a"); len(got) != 0 {
+		t.Errorf("take(a) after retagging k2 = %v, want none", got)
+	}
+	if got := x.take("b"); !slices.Equal(got, []string{"k2"}) {
+		t.Errorf("take(b) = %v, want [k2]", got)
+	}
+
+	x.reset()
+	if got := x.take("c"); len(got) != 0 {
+		t.Errorf("take(c) after reset = %v, want none", got)
+	}
+	if len(x.tags) != 0 || len(x.keys) != 0 {
+		t.Errorf("index not empty: %d tags, %d keys", len(x.tags), len(x.keys))
+	}
+}
+
+func TestRistrettoTagIndexFollowsEntries(t *testing.T) {
+	ctx := context.Background()
+	c, err := newRistretto[int64, string](RistrettoConfig{MaxCost: 100}, DefaultKeyFunc[int64]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for id := range int64(3) {
+		_ = c.SetTagged(ctx, id, "v", "domain:1")
+	}
+	c.inner.Wait()
+	_ = c.Delete(ctx, 0)
+	_ = c.DeleteMany(ctx, []int64{1})
+	if n := len(c.tags.keys); n != 1 {
+		t.Fatalf("after deleting 2 of 3 tagged entries the index holds %d keys, want 1", n)
+	}
+
+	c.clear()
+	if len(c.tags.tags) != 0 || len(c.tags.keys) != 0 {
+		t.Errorf("index not empty after clear: %d tags, %d keys", len(c.tags.tags), len(c.tags.keys))
+	}
+}
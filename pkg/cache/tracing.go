@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// noopSpan is returned when no span is started, so callers can always End it
+// without touching the parent span in ctx.
+var noopSpan = trace.SpanFromContext(context.Background())
+
+const (
+	attrHit  = attribute.Key("cache.hit")
+	attrHits = attribute.Key("cache.hits")
+	attrKeys = attribute.Key("cache.keys")
+	attrTag  = attribute.Key("cache.tag")
+)
+
+// startSpan starts a client span named name under the span in ctx.
+// Like the pgx instrumentation, it does nothing when ctx carries no recording
+// span, so background refreshes and untraced callers do not create root spans.
+func startSpan(ctx context.Context, tracer trace.Tracer, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return ctx, noopSpan
+	}
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedCache wraps a Cache (the raw L2 layer) and opens a span around every
+// call, so Redis round trips on a cache miss show up in traces.
+// Created internally by Builder when WithTracing is set.
+type tracedCache[K comparable, V any] struct {
+	inner  Cache[K, V]
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+}
+
+func newTracedCache[K comparable, V any](inner Cache[K, V], tracer trace.Tracer, name, layer string) Cache[K, V] {
+	return &tracedCache[K, V]{
+		inner:  inner,
+		tracer: tracer,
+		attrs:  []attribute.KeyValue{attrCache.String(name), attrLayer.String(layer)},
+	}
+}
+
+func (c *tracedCache[K, V]) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return startSpan(ctx, c.tracer, "cache."+op, append(attrs, c.attrs...)...)
+}
+
+func (c *tracedCache[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
+	ctx, span := c.start(ctx, "Get")
+	v, ok, err := c.inner.Get(ctx, key)
+	span.SetAttributes(attrHit.Bool(ok))
+	endSpan(span, err)
+	return v, ok, err
+}
+
+func (c *tracedCache[K, V]) Set(ctx context.Context, key K, value V) error {
+	ctx, span := c.start(ctx, "Set")
+	err := c.inner.Set(ctx, key, value)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedCache[K, V]) SetTTL(ctx context.Context, key K, value V, ttl time.Duration) error {
+	ctx, span := c.start(ctx, "SetTTL")
+	err := c.inner.SetTTL(ctx, key, value, ttl)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedCache[K, V]) Delete(ctx context.Context, key K) error {
+	ctx, span := c.start(ctx, "Delete")
+	err := c.inner.Delete(ctx, key)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedCache[K, V]) GetMany(ctx context.Context, keys []K) (map[K]V, error) {
+	ctx, span := c.start(ctx, "GetMany", attrKeys.Int(len(keys)))
+	found, err := c.inner.GetMany(ctx, keys)
+	span.SetAttributes(attrHits.Int(len(found)))
+	endSpan(span, err)
+	return found, err
+}
+
+func (c *tracedCache[K, V]) SetMany(ctx context.Context, entries map[K]V) error {
+	ctx, span := c.start(ctx, "SetMany", attrKeys.Int(len(entries)))
+	err := c.inner.SetMany(ctx, entries)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedCache[K, V]) DeleteMany(ctx context.Context, keys []K) error {
+	ctx, span := c.start(ctx, "DeleteMany", attrKeys.Int(len(keys)))
+	err := c.inner.DeleteMany(ctx, keys)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedCache[K, V]) SetTagged(ctx context.Context, key K, value V, tags ...string) error {
+	ctx, span := c.start(ctx, "SetTagged")
+	err := c.inner.SetTagged(ctx, key, value, tags...)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedCache[K, V]) InvalidateTag(ctx context.Context, tag string) error {
+	_, err := c.invalidateTagKeys(ctx, tag)
+	return err
+}
+
+func (c *tracedCache[K, V]) invalidateTagKeys(ctx context.Context, tag string) ([]string, error) {
+	ctx, span := c.start(ctx, "InvalidateTag", attrTag.String(tag))
+	keys, err := invalidateTagKeys(ctx, c.inner, tag)
+	span.SetAttributes(attrKeys.Int(len(keys)))
+	endSpan(span, err)
+	return keys, err
+}
+
+func (c *tracedCache[K, V]) Close() error { return c.inner.Close() }
+
+// instrumentLoader wraps fn so that every call opens a "cache.Load" span
+// (when tracer is non-nil) and reports Stats.LoadDuration (when stats is non-nil).
+func instrumentLoader[K comparable, V any](fn LoadFunc[K, V], stats Stats, tracer trace.Tracer, name string) LoadFunc[K, V] {
+	if fn == nil || (stats == nil && tracer == nil) {
+		return fn
+	}
+	return func(ctx context.Context, key K) (V, error) {
+		span := noopSpan
+		if tracer != nil {
+			ctx, span = startSpan(ctx, tracer, "cache.Load", attrCache.String(name))
+		}
+		start := time.Now()
+		v, err := fn(ctx, key)
+		if stats != nil {
+			stats.LoadDuration(ctx, name, time.Since(start), err)
+		}
+		span.SetAttributes(attrOutcome.String(loadOutcome(err)))
+		endSpan(span, loadSpanError(err))
+		return v, err
+	}
+}
+
+// loadSpanError drops ErrNotFound: a known-absent key is not a failed load.
+func loadSpanError(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// instrumentBatchLoader is instrumentLoader for BatchLoadFunc.
+func instrumentBatchLoader[K comparable, V any](fn BatchLoadFunc[K, V], stats Stats, tracer trace.Tracer, name string) BatchLoadFunc[K, V] {
+	if fn == nil || (stats == nil && tracer == nil) {
+		return fn
+	}
+	return func(ctx context.Context, keys []K) (map[K]V, error) {
+		span := noopSpan
+		if tracer != nil {
+			ctx, span = startSpan(ctx, tracer, "cache.LoadMany", attrCache.String(name), attrKeys.Int(len(keys)))
+		}
+		start := time.Now()
+		loaded, err := fn(ctx, keys)
+		if stats != nil {
+			stats.LoadDuration(ctx, name, time.Since(start), err)
+		}
+		span.SetAttributes(attrOutcome.String(loadOutcome(err)))
+		endSpan(span, loadSpanError(err))
+		return loaded, err
+	}
+}
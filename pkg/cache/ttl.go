@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// TTLFunc returns the lifetime of an entry from its key and value, e.g. a
+// shorter TTL for empty results or a longer one for rarely changing records.
+// Returning zero keeps the layer's configured TTL.
+type TTLFunc[K comparable, V any] func(key K, value V) time.Duration
+
+// CostFunc returns the Ristretto cost of a value. With a size-based cost,
+// RistrettoConfig.MaxCost becomes a memory budget and one large value no
+// longer counts the same as one small one.
+type CostFunc[V any] func(value V) int64
+
+// CodecCost returns a CostFunc that charges the encoded size of a value in
+// bytes. Every L1 write marshals the value once more; prefer a cheaper
+// estimate for large or hot values.
+func CodecCost[V any](codec Codec[V]) CostFunc[V] {
+	return func(v V) int64 {
+		data, err := codec.Marshal(v)
+		if err != nil {
+			return 1
+		}
+		return max(int64(len(data)), 1)
+	}
+}
+
+// ttlPolicy computes per-entry TTLs from an optional TTLFunc and jitter.
+// A nil *ttlPolicy returns the layer default unchanged.
+type ttlPolicy[K comparable, V any] struct {
+	fn     TTLFunc[K, V]
+	jitter float64
+}
+
+func newTTLPolicy[K comparable, V any](fn TTLFunc[K, V], jitter float64) *ttlPolicy[K, V] {
+	if fn == nil && jitter <= 0 {
+		return nil
+	}
+	return &ttlPolicy[K, V]{fn: fn, jitter: jitter}
+}
+
+// ttl returns the TTL for an entry written with the layer default base.
+// A TTLFunc result above limit (when limit > 0) is capped to it, so L1 never
+// outlives its own TTL in a multilevel cache. Jitter is applied last.
+func (p *ttlPolicy[K, V]) ttl(base, limit time.Duration, key K, value V) time.Duration {
+	if p == nil {
+		return base
+	}
+	if p.fn != nil {
+		if d := p.fn(key, value); d > 0 {
+			base = d
+			if limit > 0 && base > limit {
+				base = limit
+			}
+		}
+	}
+	if base > 0 {
+		base = Jitter(base, p.jitter)
+	}
+	return base
+}
+
+// variable reports whether entries may get different TTLs.
+func (p *ttlPolicy[K, V]) variable() bool {
+	return p != nil
+}
+
+type ctxKeyLoadTTL struct{}
+
+// WithLoadTTL returns a context that makes a loading cache store the value
+// produced by a load on this call with ttl in every layer, instead of the
+// configured TTLs and TTLFunc. It has no effect on cache hits, on loads
+// started by another caller (the shared load keeps that caller's TTL), or on
+// BatchLoadFunc loads.
+func WithLoadTTL(ctx context.Context, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, ctxKeyLoadTTL{}, ttl)
+}
+
+func loadTTLFrom(ctx context.Context) time.Duration {
+	d, _ := ctx.Value(ctxKeyLoadTTL{}).(time.Duration)
+	return d
+}
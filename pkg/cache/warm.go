@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Warm preloads keys into c with at most concurrency parallel reads.
+//
+// Every key goes through c.Get, so on a cache built WithLoader missing keys
+// are loaded from the source and keys already in L2 are copied into the cold
+// L1. With a BatchLoadFunc, concurrent misses are coalesced into batches.
+// Keys the loader reports as ErrNotFound are skipped.
+//
+// Warm stops early when ctx is cancelled. Load errors do not stop it; they
+// are joined into the returned error.
+//
+// Example — preload the most active contacts on startup:
+//
+//	err := cache.Warm(ctx, contacts, slices.Values(ids), 16)
+func Warm[K comparable, V any](ctx context.Context, c Cache[K, V], keys iter.Seq[K], concurrency int) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		g    errgroup.Group
+		mu   sync.Mutex
+		errs []error
+	)
+	g.SetLimit(concurrency)
+
+	for key := range keys {
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			_, _, err := c.Get(ctx, key)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}